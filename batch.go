@@ -0,0 +1,140 @@
+package dokusu
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// BatchOptions are how a batch of puzzles is generated
+type BatchOptions struct {
+	GenOptions // of each puzzle; the seed is the batch's
+
+	Count         int           // puzzles to generate
+	Workers       int           // puzzles generated at once; 0 for one per CPU
+	Band          *Band         // if set, puzzles are rated in it
+	PuzzleTimeout time.Duration // give up on a puzzle after it; 0 for no limit
+}
+
+// ErrGaveUp is returned by GenerateBatch when it gives up on as
+// many puzzles in a row as it has to generate, or as it has workers
+// if more, e.g. if no puzzle can be made in time
+var ErrGaveUp = errors.New("gave up on too many puzzles in a row")
+
+// a puzzle generated by a worker, or why it was given up on
+type batchResult struct {
+	puzzle Puzzle
+	err    error
+}
+
+// GenerateBatch generates puzzles on a pool of workers and calls
+// emit with each one as soon as it is ready, from one goroutine
+// at a time; the order depends on which puzzles are ready first.
+// Each puzzle has a seed drawn from the batch's, that generates
+// it again. A puzzle given up on after its timeout is replaced
+// by another one, unless too many are given up on, see ErrGaveUp.
+// Stops when Count puzzles are emitted, emit fails or the context
+// is done; returns the puzzles given up on.
+func GenerateBatch(ctx context.Context, opts BatchOptions, emit func(Puzzle) error) (int, error) {
+	if opts.Count < 1 {
+		return 0, nil
+	}
+	workers := opts.Workers
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	if opts.Seed == 0 {
+		opts.Seed = newSeed()
+	}
+	seeds := rand.New(rand.NewSource(opts.Seed))
+
+	// stopped once the batch is done, whatever the reason
+	batch, stop := context.WithCancel(ctx)
+	defer stop()
+
+	jobs := make(chan int64)
+	go func() {
+		defer close(jobs)
+		for {
+			select {
+			case jobs <- seeds.Int63():
+			case <-batch.Done():
+				return
+			}
+		}
+	}()
+
+	results := make(chan batchResult)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for seed := range jobs {
+				p, err := opts.puzzle(batch, seed)
+				select {
+				case results <- batchResult{p, err}:
+				case <-batch.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	maxInARow := opts.Count
+	if maxInARow < workers {
+		maxInARow = workers
+	}
+	emitted, gaveUp, inARow := 0, 0, 0
+	var err error
+	for r := range results {
+		if batch.Err() != nil {
+			continue // drain the workers
+		}
+		if r.err != nil {
+			gaveUp++
+			if inARow++; inARow == maxInARow {
+				err = ErrGaveUp
+				stop()
+			}
+			continue
+		}
+		inARow = 0
+		if err = emit(r.puzzle); err != nil {
+			stop()
+			continue
+		}
+		emitted++
+		if emitted == opts.Count {
+			stop()
+		}
+	}
+
+	if err == nil && emitted < opts.Count {
+		err = ctx.Err()
+	}
+	return gaveUp, err
+}
+
+// puzzle of a batch generated from a seed
+func (opts BatchOptions) puzzle(ctx context.Context, seed int64) (Puzzle, error) {
+	if opts.PuzzleTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.PuzzleTimeout)
+		defer cancel()
+	}
+	g := opts.GenOptions
+	g.Seed = seed
+	if opts.Band != nil {
+		p, _, err := generateRated(ctx, g, *opts.Band, 0)
+		return p, err
+	}
+	return generate(ctx, g)
+}
@@ -0,0 +1,85 @@
+package dokusu
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGenerateBatch(t *testing.T) {
+	opts := BatchOptions{
+		GenOptions: GenOptions{Symmetry: RotationalSymmetry, Seed: 3},
+		Count:      20,
+		Workers:    4,
+	}
+	seen := make(map[string]int64)
+	gaveUp, err := GenerateBatch(context.Background(), opts, func(p Puzzle) error {
+		seen[p.Board.Line()] = p.Seed
+		return nil
+	})
+	if err != nil || gaveUp != 0 {
+		t.Fatalf("GenerateBatch = %d, %v", gaveUp, err)
+	}
+	if len(seen) != opts.Count {
+		t.Fatalf("%d different puzzles; want %d", len(seen), opts.Count)
+	}
+
+	// every puzzle is generated again by its seed
+	for line, seed := range seen {
+		g := opts.GenOptions
+		g.Seed = seed
+		if p := Generate(g); p.Board.Line() != line {
+			t.Errorf("seed %d generated %s; want %s", seed, p.Board.Line(), line)
+		}
+	}
+}
+
+func TestGenerateBatchRated(t *testing.T) {
+	band := TierBand(Medium)
+	opts := BatchOptions{Count: 3, Workers: 2, Band: &band}
+	_, err := GenerateBatch(context.Background(), opts, func(p Puzzle) error {
+		if r := p.Board.Grade(); !band.Contains(r) {
+			t.Errorf("puzzle rated %s; want %s", r, band)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGenerateBatchStop(t *testing.T) {
+	// puzzles time out before they are done, until too many have
+	opts := BatchOptions{Count: 5, Workers: 2, PuzzleTimeout: time.Nanosecond}
+	gaveUp, err := GenerateBatch(context.Background(), opts, func(p Puzzle) error {
+		t.Error("puzzle emitted after its timeout")
+		return nil
+	})
+	if err != ErrGaveUp || gaveUp != 5 {
+		t.Errorf("GenerateBatch = %d, %v; want 5 puzzles given up on and %v", gaveUp, err, ErrGaveUp)
+	}
+
+	// or until the batch times out
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	opts = BatchOptions{Count: 1000, PuzzleTimeout: time.Minute}
+	_, err = GenerateBatch(ctx, opts, func(p Puzzle) error { return nil })
+	if err != context.DeadlineExceeded {
+		t.Errorf("GenerateBatch = %v; want a deadline", err)
+	}
+
+	// an error emitting stops the batch
+	full := errors.New("booklet full")
+	emitted := 0
+	_, err = GenerateBatch(context.Background(), BatchOptions{Count: 100}, func(p Puzzle) error {
+		if emitted == 3 {
+			return full
+		}
+		emitted++
+		return nil
+	})
+	if err != full || emitted != 3 {
+		t.Errorf("GenerateBatch = %v after %d puzzles; want %v after 3", err, emitted, full)
+	}
+}
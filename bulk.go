@@ -0,0 +1,122 @@
+package dokusu
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Report of a puzzle solved and graded by SolveAll
+type Report struct {
+	Line     int // of the puzzle in the input, from 1
+	Puzzle   Board
+	Solution Board // the first found, if any
+	Unique   Uniqueness
+	Rating   Rating        // only of puzzles with a single solution
+	Time     time.Duration // to solve, looking for a second solution too
+	Err      error         // the line is not a valid puzzle
+}
+
+// SolveAll reads puzzles, one per line in text, and solves and
+// grades them on workers, 0 for one per CPU; empty lines and
+// those starting with # are skipped, as is anything after a tab,
+// like the seeds written with generated puzzles. It calls report with each
+// one in the order read, as soon as it and those before it are
+// done. Stops when the input ends, report fails or the context
+// is done.
+func SolveAll(ctx context.Context, r io.Reader, workers int, report func(Report) error) error {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	// puzzles are sent to workers, and where their report will
+	// be to the loop below, in the order read
+	type job struct {
+		line int
+		text string
+		done chan Report
+	}
+	jobs := make(chan job)
+	order := make(chan chan Report, 2*workers)
+	var readErr error
+	go func() {
+		defer close(order)
+		defer close(jobs)
+		s := bufio.NewScanner(r)
+		for n := 1; s.Scan(); n++ {
+			text := strings.TrimSpace(s.Text())
+			if text == "" || strings.HasPrefix(text, "#") {
+				continue
+			}
+			if i := strings.IndexByte(text, '\t'); i >= 0 {
+				text = strings.TrimSpace(text[:i])
+			}
+			j := job{n, text, make(chan Report, 1)}
+			select {
+			case order <- j.done:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- j:
+			case <-ctx.Done():
+				return
+			}
+		}
+		readErr = s.Err()
+	}()
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for j := range jobs {
+				j.done <- solveReport(j.line, j.text)
+			}
+		}()
+	}
+
+	for done := range order {
+		select {
+		case rep := <-done:
+			if err := report(rep); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if readErr != nil {
+		return readErr
+	}
+	return ctx.Err()
+}
+
+// solve and grade the puzzle on a line
+func solveReport(line int, text string) Report {
+	rep := Report{Line: line}
+	b, err := ParseText(text)
+	if err == nil {
+		err = b.Check(false)
+	}
+	if err != nil {
+		rep.Err = err
+		return rep
+	}
+	rep.Puzzle = b
+
+	start := time.Now()
+	u, found := b.Uniqueness()
+	rep.Time = time.Since(start)
+	rep.Unique = u
+	if len(found) > 0 {
+		rep.Solution = found[0]
+	}
+	if u == UniqueSolution {
+		rep.Rating = b.Grade()
+	}
+	return rep
+}
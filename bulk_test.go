@@ -0,0 +1,69 @@
+package dokusu
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSolveAll(t *testing.T) {
+	in := "# test puzzles\n" + strings.Join(testPuzzles, "\n\n") + "\n12\n" + strings.Repeat(".", 81) + "\n"
+	var reports []Report
+	err := SolveAll(context.Background(), strings.NewReader(in), 4, func(r Report) error {
+		reports = append(reports, r)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != len(testPuzzles)+2 {
+		t.Fatalf("%d reports; want %d", len(reports), len(testPuzzles)+2)
+	}
+
+	// in the order read, with the lines numbered from 1
+	for i, p := range testPuzzles {
+		r := reports[i]
+		if r.Line != 2+2*i || r.Err != nil {
+			t.Fatalf("report %d of line %d: %v", i, r.Line, r.Err)
+		}
+		b := parseBoard(t, p)
+		if !sameNumbers(r.Puzzle, b) || r.Unique != UniqueSolution || !r.Solution.IsComplete() {
+			t.Errorf("line %d: %s, %s", r.Line, r.Unique, r.Solution.Line())
+		}
+		if want := b.Grade(); r.Rating.String() != want.String() {
+			t.Errorf("line %d rated %s; want %s", r.Line, r.Rating, want)
+		}
+	}
+
+	bad, empty := reports[len(testPuzzles)], reports[len(testPuzzles)+1]
+	if bad.Err == nil {
+		t.Errorf("line %d read as a puzzle", bad.Line)
+	}
+	if empty.Unique != MultipleSolutions || empty.Rating.Steps != 0 {
+		t.Errorf("empty board: %s, rated %s", empty.Unique, empty.Rating)
+	}
+}
+
+func TestSolveAllStop(t *testing.T) {
+	in := strings.Repeat(testPuzzles[0]+"\n", 50)
+	stopped := errors.New("stopped")
+	n := 0
+	err := SolveAll(context.Background(), strings.NewReader(in), 2, func(r Report) error {
+		n++
+		if n == 5 {
+			return stopped
+		}
+		return nil
+	})
+	if err != stopped || n != 5 {
+		t.Errorf("SolveAll = %v after %d reports", err, n)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = SolveAll(ctx, strings.NewReader(in), 2, func(r Report) error { return nil })
+	if err != context.Canceled {
+		t.Errorf("SolveAll = %v; want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,552 @@
+package dokusu
+
+import "fmt"
+
+// Link between two candidates of a chain; a cell's Number is
+// the candidate's number. A strong link means that if From is
+// false then To is true; a weak one that if From is true then
+// To is false.
+type Link struct {
+	From   Cell
+	To     Cell
+	Strong bool
+}
+
+// e.g. [00]1=[00]2 for a strong link, [00]2-[05]2 for a weak one
+func (l Link) String() string {
+	sign := "-"
+	if l.Strong {
+		sign = "="
+	}
+	return fmt.Sprintf("%s%d%s%s%d", l.From, l.From.Number, sign, l.To, l.To.Number)
+}
+
+// longest chain (in links) searched for
+const maxChain = 16
+
+// candidates are numbered by cell and number, see key
+func key(row, col, n int) int {
+	return (row*9+col)*10 + n
+}
+
+// candidate for a key, with Number set
+func candidate(k int) Cell {
+	return Cell{row: k / 10 / 9, col: k / 10 % 9, Number: k % 10}
+}
+
+// Highlight a step on the board: cells of the pattern and those
+// true in a chain are candid, cells false in a chain are selected,
+// and cells changed by the step blink
+func (b *Board) Highlight(s Step) {
+	for _, c := range s.Cells {
+		b[c.row][c.col].Candid = true
+	}
+	for _, l := range s.Chain {
+		on, off := l.To, l.From
+		if !l.Strong {
+			on, off = l.From, l.To
+		}
+		b[on.row][on.col].Candid = true
+		b[off.row][off.col].Selected = true
+	}
+	for _, c := range s.Placed {
+		b[c.row][c.col].Blink = true
+	}
+	for _, c := range s.Eliminated {
+		b[c.row][c.col].Blink = true
+	}
+}
+
+// simple coloring: cells linked by strong links on a number take
+// alternating colors, one of which is true. If two cells of the
+// same color see each other, that color is false (color wrap);
+// cells seeing both colors cannot hold the number (color trap)
+func findColoring(b *Board) (Step, bool) {
+	for n := 1; n < 10; n++ {
+		var adj [81][]int
+		for _, l := range b.strongLinks(n) {
+			i, j := l[0].row*9+l[0].col, l[1].row*9+l[1].col
+			adj[i] = append(adj[i], j)
+			adj[j] = append(adj[j], i)
+		}
+
+		var color [81]int // 0 for no color, 1 or 2
+		for start := 0; start < 81; start++ {
+			if color[start] != 0 || len(adj[start]) == 0 {
+				continue
+			}
+
+			// color the cells of this cluster, keeping the links used
+			var cells [3][]Cell
+			var chain []Link
+			color[start] = 1
+			cells[1] = append(cells[1], Cell{row: start / 9, col: start % 9})
+			queue := []int{start}
+			for len(queue) > 0 {
+				i := queue[0]
+				queue = queue[1:]
+				for _, j := range adj[i] {
+					if color[j] != 0 {
+						continue
+					}
+					color[j] = 3 - color[i]
+					c := Cell{row: j / 9, col: j % 9}
+					cells[color[j]] = append(cells[color[j]], c)
+					chain = append(chain, Link{
+						From:   Cell{row: i / 9, col: i % 9, Number: n},
+						To:     Cell{row: j / 9, col: j % 9, Number: n},
+						Strong: color[i] == 2, // color 1 is taken as true
+					})
+					queue = append(queue, j)
+				}
+			}
+			if len(chain) < 2 {
+				continue // a single strong link, nothing to color
+			}
+
+			var elim []Cell
+			for c := 1; c < 3 && len(elim) == 0; c++ {
+				if wrapped(cells[c]) {
+					for _, w := range cells[c] {
+						elim = append(elim, Cell{row: w.row, col: w.col, Number: n})
+					}
+				}
+			}
+			if len(elim) == 0 {
+				for _, e := range b.eliminateSeen(n) {
+					if color[e.row*9+e.col] == 0 && seesAny(e, cells[1]) && seesAny(e, cells[2]) {
+						elim = append(elim, e)
+					}
+				}
+			}
+			if len(elim) > 0 {
+				return Step{
+					Technique:  SimpleColoring,
+					Cells:      append(cells[1], cells[2]...),
+					Digits:     []int{n},
+					Eliminated: elim,
+					Chain:      chain,
+				}, true
+			}
+		}
+	}
+	return Step{}, false
+}
+
+// wrapped reports if any two of the cells see each other
+func wrapped(cells []Cell) bool {
+	for i := range cells {
+		for j := i + 1; j < len(cells); j++ {
+			if sees(cells[i], cells[j]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// seesAny reports if a cell sees any of the cells
+func seesAny(c Cell, cells []Cell) bool {
+	for _, o := range cells {
+		if sees(c, o) {
+			return true
+		}
+	}
+	return false
+}
+
+// x-cycle: an alternating inference chain on a single number
+func findXCycle(b *Board) (Step, bool) {
+	return findChain(b, XCycle)
+}
+
+// alternating inference chain (AIC): candidates linked alternately
+// by strong and weak links, starting and ending with a strong link;
+// either the first or the last candidate is true, so candidates that
+// see both are removed
+func findAIC(b *Board) (Step, bool) {
+	return findChain(b, AIC)
+}
+
+// findChain looks for the shortest chain from every candidate
+// and returns the shortest of those that eliminate something
+func findChain(b *Board, t Technique) (Step, bool) {
+	var best Step
+	found := false
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			if b[row][col].Number > 0 {
+				continue
+			}
+			for _, n := range b[row][col].Marks {
+				s, ok := b.searchChain(key(row, col, n), t == XCycle)
+				if ok && (!found || len(s.Chain) < len(best.Chain)) {
+					best, found = s, true
+					best.Technique = t
+				}
+			}
+		}
+	}
+	return best, found
+}
+
+// searchChain does a breadth first search of alternating chains
+// starting with candidate start being false; single keeps the
+// chain on start's number
+func (b *Board) searchChain(start int, single bool) (Step, bool) {
+	type state struct {
+		k  int
+		on bool
+	}
+	parent := map[state]state{}
+	depth := map[state]int{{start, false}: 0}
+	queue := []state{{start, false}}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if depth[cur] >= maxChain {
+			continue
+		}
+
+		var next []int
+		if cur.on {
+			next = b.weakLinks(cur.k, single)
+		} else {
+			next = b.strongLinksFrom(cur.k, single)
+		}
+		for _, k := range next {
+			st := state{k, !cur.on}
+			if _, seen := depth[st]; seen {
+				continue
+			}
+			depth[st] = depth[cur] + 1
+			parent[st] = cur
+			queue = append(queue, st)
+			if !st.on {
+				continue
+			}
+
+			// start false makes k true, so one of them is true
+			s, ok := b.chainEnds(start, k)
+			if !ok {
+				continue
+			}
+			for p := st; p != (state{start, false}); p = parent[p] {
+				pp := parent[p]
+				l := Link{From: candidate(pp.k), To: candidate(p.k), Strong: p.on}
+				s.Chain = append([]Link{l}, s.Chain...)
+			}
+			return s, true
+		}
+	}
+	return Step{}, false
+}
+
+// chainEnds returns the eliminations (or placement) that follow
+// from one of two candidates being true
+func (b *Board) chainEnds(k1, k2 int) (Step, bool) {
+	c1, c2 := candidate(k1), candidate(k2)
+	s := Step{Cells: []Cell{c1, c2}, Digits: []int{c1.Number}}
+	if c2.Number != c1.Number {
+		s.Digits = append(s.Digits, c2.Number)
+	}
+
+	switch {
+	case k1 == k2: // false implies true, so it is true
+		s.Placed = []Cell{c1}
+
+	case c1.Number == c2.Number:
+		s.Eliminated = b.eliminateSeen(c1.Number, c1, c2)
+
+	case sameCell(c1, c2):
+		for _, n := range b[c1.row][c1.col].Marks {
+			if n != c1.Number && n != c2.Number {
+				s.Eliminated = append(s.Eliminated, Cell{row: c1.row, col: c1.col, Number: n})
+			}
+		}
+
+	case sees(c1, c2):
+		if b[c1.row][c1.col].HasMark(c2.Number) {
+			s.Eliminated = append(s.Eliminated, Cell{row: c1.row, col: c1.col, Number: c2.Number})
+		}
+		if b[c2.row][c2.col].HasMark(c1.Number) {
+			s.Eliminated = append(s.Eliminated, Cell{row: c2.row, col: c2.col, Number: c1.Number})
+		}
+	}
+
+	return s, len(s.Placed)+len(s.Eliminated) > 0
+}
+
+// weakLinks returns candidates that are false if candidate k is true
+func (b *Board) weakLinks(k int, single bool) []int {
+	c := candidate(k)
+	var ks []int
+	for _, p := range peers(c.row, c.col) {
+		if b[p.row][p.col].Number == 0 && b[p.row][p.col].HasMark(c.Number) {
+			ks = append(ks, key(p.row, p.col, c.Number))
+		}
+	}
+	if !single {
+		for _, n := range b[c.row][c.col].Marks {
+			if n != c.Number {
+				ks = append(ks, key(c.row, c.col, n))
+			}
+		}
+	}
+	return ks
+}
+
+// strongLinksFrom returns candidates that are true if candidate k is false
+func (b *Board) strongLinksFrom(k int, single bool) []int {
+	c := candidate(k)
+	var ks []int
+	for _, u := range []Unit{rowUnit(c.row), colUnit(c.col), boxUnit(c.row, c.col)} {
+		pos := b.positions(u, c.Number)
+		if len(pos) != 2 {
+			continue
+		}
+		o := pos[0]
+		if sameCell(o, c) {
+			o = pos[1]
+		}
+		ks = append(ks, key(o.row, o.col, c.Number))
+	}
+	if !single && len(b[c.row][c.col].Marks) == 2 {
+		for _, n := range b[c.row][c.col].Marks {
+			if n != c.Number {
+				ks = append(ks, key(c.row, c.col, n))
+			}
+		}
+	}
+	return ks
+}
+
+// branch is what follows from assuming a candidate true,
+// placing numbers with singles only
+type branch struct {
+	board       Board
+	start       int
+	on          map[int]bool // candidates found true, or false
+	cause       map[int]int  // candidate that made another true or false
+	seq         map[int]int  // order in which candidates became false
+	broken      bool         // assumption led to a contradiction
+	contradicts int          // candidate whose removal broke the board
+}
+
+// propagate assumes candidate k is true and follows
+// with naked and hidden singles
+func (b *Board) propagate(k int) *branch {
+	br := &branch{
+		board: *b,
+		start: k,
+		on:    map[int]bool{},
+		cause: map[int]int{},
+		seq:   map[int]int{},
+	}
+	br.place(candidate(k))
+	for !br.broken {
+		s, ok := findNakedSingle(&br.board)
+		if !ok {
+			s, ok = findHiddenSingle(&br.board)
+		}
+		if !ok {
+			break
+		}
+		c := s.Placed[0]
+		br.cause[key(c.row, c.col, c.Number)] = br.reason(s)
+		br.place(c)
+	}
+	return br
+}
+
+// place a candidate on the branch's board, recording what became false
+func (br *branch) place(c Cell) {
+	k := key(c.row, c.col, c.Number)
+	br.on[k] = true
+	off := func(row, col, n int) {
+		ok := key(row, col, n)
+		br.on[ok] = false
+		br.cause[ok] = k
+		br.seq[ok] = len(br.seq) + 1
+	}
+	for _, n := range br.board[c.row][c.col].Marks {
+		if n != c.Number {
+			off(c.row, c.col, n)
+		}
+	}
+	for _, p := range peers(c.row, c.col) {
+		if br.board[p.row][p.col].Number == 0 && br.board[p.row][p.col].HasMark(c.Number) {
+			off(p.row, p.col, c.Number)
+		}
+	}
+	br.board.place(c.row, c.col, c.Number)
+
+	// an empty cell with no marks, or a number with no place in a unit
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			if br.board[row][col].Number == 0 && len(br.board[row][col].Marks) == 0 {
+				br.broken = true
+				br.contradicts = br.last([]Cell{{row: row, col: col}}, 0)
+				return
+			}
+		}
+	}
+	for _, u := range units {
+		for n := 1; n < 10; n++ {
+			if !br.board.placed(u, n) && len(br.board.positions(u, n)) == 0 {
+				br.broken = true
+				br.contradicts = br.last(u.cells[:], n)
+				return
+			}
+		}
+	}
+}
+
+// last returns the candidate of the cells that became false last;
+// n is the number to look for, or 0 for any number
+func (br *branch) last(cells []Cell, n int) int {
+	best, bestSeq := br.start, 0
+	for _, c := range cells {
+		for m := 1; m < 10; m++ {
+			if n != 0 && m != n {
+				continue
+			}
+			k := key(c.row, c.col, m)
+			if s, ok := br.seq[k]; ok && s > bestSeq {
+				best, bestSeq = k, s
+			}
+		}
+	}
+	return best
+}
+
+// reason returns the candidate whose removal made a single
+func (br *branch) reason(s Step) int {
+	c := s.Placed[0]
+	if s.Technique == NakedSingle {
+		return br.last([]Cell{c}, 0)
+	}
+	var others []Cell
+	for _, o := range s.Units[0].cells {
+		if !sameCell(o, c) {
+			others = append(others, o)
+		}
+	}
+	return br.last(others, c.Number)
+}
+
+// trace returns the links from the assumption to candidate k
+func (br *branch) trace(k int) []Link {
+	var chain []Link
+	for k != br.start {
+		from, ok := br.cause[k]
+		if !ok {
+			break
+		}
+		l := Link{From: candidate(from), To: candidate(k), Strong: br.on[k]}
+		chain = append([]Link{l}, chain...)
+		k = from
+	}
+	return chain
+}
+
+// cell forcing chain: every candidate of a cell leads to the same
+// conclusion, so that conclusion is true
+func findCellForcingChain(b *Board) (Step, bool) {
+	for _, c := range b.cellsWithMarks(2) {
+		var ks []int
+		for _, n := range b[c.row][c.col].Marks {
+			ks = append(ks, key(c.row, c.col, n))
+		}
+		if s, ok := b.forcing(ks); ok {
+			s.Technique = CellForcingChain
+			s.Cells = []Cell{c}
+			s.Digits = b[c.row][c.col].Marks
+			return s, true
+		}
+	}
+	return Step{}, false
+}
+
+// unit forcing chain: every place of a number in a unit leads
+// to the same conclusion, so that conclusion is true
+func findUnitForcingChain(b *Board) (Step, bool) {
+	for _, u := range units {
+		for n := 1; n < 10; n++ {
+			pos := b.positions(u, n)
+			if len(pos) != 2 {
+				continue
+			}
+			var ks []int
+			for _, c := range pos {
+				ks = append(ks, key(c.row, c.col, n))
+			}
+			if s, ok := b.forcing(ks); ok {
+				s.Technique = UnitForcingChain
+				s.Units = []Unit{u}
+				s.Cells = pos
+				s.Digits = []int{n}
+				return s, true
+			}
+		}
+	}
+	return Step{}, false
+}
+
+// forcing assumes each candidate in turn, one of which must be true,
+// and looks for a conclusion common to all non-contradicting branches
+func (b *Board) forcing(ks []int) (Step, bool) {
+	var branches, valid []*branch
+	for _, k := range ks {
+		br := b.propagate(k)
+		branches = append(branches, br)
+		if !br.broken {
+			valid = append(valid, br)
+		}
+	}
+	if len(valid) == 0 {
+		return Step{}, false // the board is broken
+	}
+
+	// placements first, then eliminations
+	for _, want := range []bool{true, false} {
+		for row := 0; row < 9; row++ {
+			for col := 0; col < 9; col++ {
+				if b[row][col].Number > 0 {
+					continue
+				}
+				for _, n := range b[row][col].Marks {
+					k := key(row, col, n)
+					common := true
+					for _, br := range valid {
+						if on, ok := br.on[k]; !ok || on != want {
+							common = false
+							break
+						}
+					}
+					if !common {
+						continue
+					}
+
+					s := Step{}
+					c := Cell{row: row, col: col, Number: n}
+					if want {
+						s.Placed = []Cell{c}
+					} else {
+						s.Eliminated = []Cell{c}
+					}
+					for _, br := range branches {
+						if br.broken {
+							s.Chain = append(s.Chain, br.trace(br.contradicts)...)
+						} else {
+							s.Chain = append(s.Chain, br.trace(k)...)
+						}
+					}
+					return s, true
+				}
+			}
+		}
+	}
+	return Step{}, false
+}
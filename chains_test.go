@@ -0,0 +1,129 @@
+package dokusu
+
+import (
+	"testing"
+)
+
+func TestColoringTrap(t *testing.T) {
+	b := markedBoard(nil)
+	unmark(&b, rowUnit(0), 5, [2]int{0, 0}, [2]int{0, 4})
+	unmark(&b, colUnit(4), 5, [2]int{0, 4}, [2]int{2, 4})
+	unmark(&b, rowUnit(2), 5, [2]int{2, 4}, [2]int{2, 8})
+
+	s, ok := findColoring(&b)
+	if !ok {
+		t.Fatal("simple coloring not found")
+	}
+	checkEliminated(t, s, map[[2]int][]int{{1, 3}: {5}, {1, 5}: {5}})
+	if len(s.Chain) != 3 {
+		t.Errorf("got chain %v; want 3 links", s.Chain)
+	}
+}
+
+func TestColoringWrap(t *testing.T) {
+	b := markedBoard(nil)
+	unmark(&b, rowUnit(0), 5, [2]int{0, 0}, [2]int{0, 4})
+	unmark(&b, colUnit(4), 5, [2]int{0, 4}, [2]int{3, 4})
+	unmark(&b, rowUnit(3), 5, [2]int{3, 4}, [2]int{3, 1})
+	unmark(&b, colUnit(1), 5, [2]int{3, 1}, [2]int{1, 1})
+
+	s, ok := findColoring(&b)
+	if !ok {
+		t.Fatal("simple coloring not found")
+	}
+	checkEliminated(t, s, map[[2]int][]int{{0, 0}: {5}, {1, 1}: {5}, {3, 4}: {5}})
+}
+
+func TestXCycle(t *testing.T) {
+	b := markedBoard(nil)
+	unmark(&b, colUnit(0), 5, [2]int{0, 0}, [2]int{6, 0})
+	unmark(&b, colUnit(3), 5, [2]int{6, 3}, [2]int{1, 3})
+
+	s, ok := findXCycle(&b)
+	if !ok {
+		t.Fatal("x-cycle not found")
+	}
+	checkEliminated(t, s, map[[2]int][]int{{0, 4}: {5}, {0, 5}: {5}, {1, 1}: {5}, {1, 2}: {5}})
+	if len(s.Chain) != 3 || !s.Chain[0].Strong || s.Chain[1].Strong || !s.Chain[2].Strong {
+		t.Errorf("got chain %v; want strong, weak, strong links", s.Chain)
+	}
+}
+
+func TestAIC(t *testing.T) {
+	b := markedBoard(map[[2]int][]int{
+		{0, 0}: {1, 2},
+		{0, 5}: {2, 3},
+		{5, 5}: {1, 3},
+	})
+
+	s, ok := findAIC(&b)
+	if !ok {
+		t.Fatal("alternating inference chain not found")
+	}
+	checkEliminated(t, s, map[[2]int][]int{{5, 0}: {1}})
+	for i, l := range s.Chain {
+		if l.Strong != (i%2 == 0) {
+			t.Errorf("link #%d %s: strong = %v", i, l, l.Strong)
+		}
+		if i > 0 && !sameCell(s.Chain[i-1].To, l.From) {
+			t.Errorf("link #%d %s does not follow %s", i, l, s.Chain[i-1])
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	b := markedBoard(map[[2]int][]int{
+		{0, 0}: {1, 2},
+		{0, 5}: {2, 3},
+		{5, 5}: {1, 3},
+	})
+	s, ok := findAIC(&b)
+	if !ok {
+		t.Fatal("alternating inference chain not found")
+	}
+
+	b.Highlight(s)
+	t.Logf("\n%s", b.Grid())
+	if !b[5][0].Blink {
+		t.Error("eliminated cell [50] not blinking")
+	}
+	for _, l := range s.Chain {
+		if !b[l.From.row][l.From.col].Candid && !b[l.From.row][l.From.col].Selected {
+			t.Errorf("chain cell %s not highlighted", l.From)
+		}
+	}
+	b.Clear()
+}
+
+// forcing chains must agree with the solution
+func TestForcingChains(t *testing.T) {
+	for i, p := range testPuzzles {
+		b := parseBoard(t, p)
+		solution, err := b.Solve()
+		if err != nil {
+			t.Fatalf("puzzle #%d: %s", i, err)
+		}
+		b.MarkCells()
+
+		for _, find := range []func(*Board) (Step, bool){findCellForcingChain, findUnitForcingChain} {
+			s, ok := find(&b)
+			if !ok {
+				continue
+			}
+			t.Logf("puzzle #%d: %s", i, s)
+			for _, c := range s.Placed {
+				if solution[c.row][c.col].Number != c.Number {
+					t.Errorf("puzzle #%d: %s: wrong placement in %s", i, s, c)
+				}
+			}
+			for _, c := range s.Eliminated {
+				if solution[c.row][c.col].Number == c.Number {
+					t.Errorf("puzzle #%d: %s: solution eliminated from %s", i, s, c)
+				}
+			}
+			if len(s.Chain) == 0 {
+				t.Errorf("puzzle #%d: %s: no chain", i, s)
+			}
+		}
+	}
+}
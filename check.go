@@ -0,0 +1,188 @@
+package dokusu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// errors of puzzles checked for uniqueness
+var (
+	ErrNoSolution        = errors.New("puzzle has no solution")
+	ErrMultipleSolutions = errors.New("puzzle has more than one solution")
+)
+
+// CellError is a problem with a cell of a puzzle
+type CellError struct {
+	Cell Cell
+	Err  error // e.g. a *ConflictError
+}
+
+func (e *CellError) Error() string {
+	return fmt.Sprintf("cell %s: %s", e.Cell, e.Err)
+}
+
+func (e *CellError) Unwrap() error { return e.Err }
+
+// PuzzleError lists what makes a puzzle invalid: a *CellError
+// for each number out of range or clashing given, and either
+// ErrNoSolution or ErrMultipleSolutions if uniqueness is checked
+type PuzzleError struct {
+	Problems []error
+}
+
+func (e *PuzzleError) Error() string {
+	msg := e.Problems[0].Error()
+	if n := len(e.Problems) - 1; n > 0 {
+		msg += fmt.Sprintf(" (and %d more)", n)
+	}
+	return msg
+}
+
+// Check that the board is a proper puzzle: numbers from 0 to 9,
+// marks from 1 to 9 and no givens clashing, and if unique is set
+// a single solution; the error is a *PuzzleError. If no cell is
+// marked as given, all numbers are taken as givens.
+func (b *Board) Check(unique bool) error {
+	var problems []error
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			c := b[row][col]
+			if c.Number < 0 || c.Number > 9 {
+				problems = append(problems, &CellError{Cell{row: row, col: col}, fmt.Errorf("number %d out of range", c.Number)})
+			}
+			for _, m := range c.Marks {
+				if m < 1 || m > 9 {
+					problems = append(problems, &CellError{Cell{row: row, col: col}, fmt.Errorf("mark %d out of range", m)})
+				}
+			}
+		}
+	}
+
+	g := b.givens()
+	for _, e := range g.Validate() {
+		problems = append(problems, &CellError{e.At, e})
+	}
+
+	if unique && len(problems) == 0 {
+		switch u, _ := g.Uniqueness(); u {
+		case NoSolution:
+			problems = append(problems, ErrNoSolution)
+		case MultipleSolutions:
+			problems = append(problems, ErrMultipleSolutions)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &PuzzleError{problems}
+	}
+	return nil
+}
+
+// Repair clears what Check finds wrong, except uniqueness:
+// numbers and marks out of range, and of each pair of clashing
+// givens the one found last; returns the cells changed
+func (b *Board) Repair() []Cell {
+	var changed []Cell
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			c := &b[row][col]
+			fixed := false
+			if c.Number < 0 || c.Number > 9 {
+				c.Number, c.Given, fixed = 0, false, true
+			}
+			var marks []int
+			for _, m := range c.Marks {
+				if m >= 1 && m <= 9 {
+					marks = append(marks, m)
+				}
+			}
+			if len(marks) != len(c.Marks) {
+				c.Marks, fixed = marks, true
+			}
+			if fixed {
+				changed = append(changed, Cell{row: row, col: col})
+			}
+		}
+	}
+
+	// a given cleared may end other clashes
+	for {
+		g := b.givens()
+		conflicts := g.Validate()
+		if len(conflicts) == 0 {
+			break
+		}
+		at := conflicts[0].At
+		b[at.row][at.col].Number = 0
+		b[at.row][at.col].Given = false
+		changed = append(changed, at)
+	}
+
+	b.FlagInvalid()
+	return changed
+}
+
+// Redundant returns the givens that can each be removed with
+// the solution staying unique; a puzzle with none is minimal.
+// Fails with ErrNoSolution or ErrMultipleSolutions if the puzzle
+// is not unique to begin with.
+func (b *Board) Redundant() ([]Cell, error) {
+	g := b.givens()
+	switch g.CountSolutions(2) {
+	case 0:
+		return nil, ErrNoSolution
+	case 2:
+		return nil, ErrMultipleSolutions
+	}
+
+	var redundant []Cell
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			n := g[row][col].Number
+			if n == 0 {
+				continue
+			}
+			g[row][col].Number = 0
+			if g.CountSolutions(2) == 1 {
+				redundant = append(redundant, b[row][col])
+			}
+			g[row][col].Number = n
+		}
+	}
+	return redundant, nil
+}
+
+// givens of the board, or all its numbers if none is marked
+func (b *Board) givens() Board {
+	marked := false
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			marked = marked || b[row][col].Given
+		}
+	}
+
+	g := NewBoard()
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			if c := b[row][col]; c.Given || !marked {
+				g[row][col].Number = c.Number
+				g[row][col].Given = c.Given
+			}
+		}
+	}
+	return g
+}
+
+// checkShape checks that a board in JSON has 9 rows of 9 cells
+func checkShape(rows [][]json.RawMessage) error {
+	if len(rows) != 9 {
+		return fmt.Errorf("board has %d rows, want 9", len(rows))
+	}
+	for i, r := range rows {
+		if len(r) != 9 {
+			return fmt.Errorf("row %d has %d cells, want 9", i, len(r))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package dokusu
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	var tests = []struct {
+		puzzle string
+		unique bool
+		want   []string // problems found, in order
+	}{
+		{"531009620000000000000006094096038100000000300700601040060800400105020000000000000", false, nil},
+		{"551009620000000000000006094096038100000000300700601040060800400105020000000000000", false, []string{
+			"cell [01]: number 5 found in cell [00] in the same row",
+			"cell [01]: number 5 found in cell [00] in the same box",
+		}},
+		{strings.Repeat(".", 81), false, nil},
+		{strings.Repeat(".", 81), true, []string{"puzzle has more than one solution"}},
+		{"12345678.........9" + strings.Repeat(".", 63), true, []string{"puzzle has no solution"}},
+	}
+
+	for _, test := range tests {
+		b, err := ParseText(test.puzzle)
+		if err != nil {
+			t.Fatalf("ParseText(%q): %s", test.puzzle, err)
+		}
+		err = b.Check(test.unique)
+		var got []string
+		var pe *PuzzleError
+		if errors.As(err, &pe) {
+			for _, p := range pe.Problems {
+				got = append(got, p.Error())
+			}
+		} else if err != nil {
+			t.Errorf("Check(%v) of %s: %v is not a *PuzzleError", test.unique, test.puzzle, err)
+		}
+		if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+			t.Errorf("Check(%v) of %s = %q; want %q", test.unique, test.puzzle, got, test.want)
+		}
+	}
+}
+
+func TestCheckGivens(t *testing.T) {
+	b, err := ParseText("531009620000000000000006094096038100000000300700601040060800400105020000000000000")
+	if err != nil {
+		t.Fatalf("cannot read puzzle: %s", err)
+	}
+
+	// the player's numbers may clash, givens may not
+	b.Move(Move{1, 1, PlaceMove, 5})
+	if err := b.Check(false); err != nil {
+		t.Errorf("player's clash found: %s", err)
+	}
+	b[1][1].Given = true
+	var pe *PuzzleError
+	var ce *ConflictError
+	err = b.Check(false)
+	if !errors.As(err, &pe) || !errors.As(pe.Problems[0], &ce) || !sameCell(ce.At, b[1][1]) {
+		t.Errorf("clashing givens: %v", err)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	dir := t.TempDir()
+	var tests = []struct {
+		json string
+		want string // part of the error
+	}{
+		{"[[]]", "board has 1 rows, want 9"},
+		{`{"Version": 2, "Cells": [[]]}`, "board has 1 rows, want 9"},
+		{rows(9, `{"Number": "5"}`), "cannot unmarshal"},
+		{rows(9, `{"Numbr": 5}`), "unknown field"},
+		{rows(8, `{"Number": 0}`), "row 0 has 8 cells, want 9"},
+		{rows(9, `{"Number": 0}`), ""},
+		{`{"Version": 2, "Undone": -1, "Cells": ` + rows(9, `{"Number": 0}`) + `}`, "-1 moves undone out of 0"},
+		{`{"Version": 2, "Undone": 1, "Cells": ` + rows(9, `{"Number": 0}`) + `}`, "1 moves undone out of 0"},
+		{`{"Version": 2, "History": ["r0c0=1"], "Cells": ` + rows(9, `{"Number": 0}`) + `}`, "moves saved without the board"},
+	}
+
+	for i, test := range tests {
+		f := filepath.Join(dir, "puzzle.json")
+		if err := ioutil.WriteFile(f, []byte(test.json), 0600); err != nil {
+			t.Fatal(err)
+		}
+		b := NewBoard()
+		err := b.Load(f)
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("test %d: Load = %v", i, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("test %d: Load = %v; want %q", i, err, test.want)
+		}
+	}
+}
+
+// rows of a board in JSON, each of n cells
+func rows(n int, cell string) string {
+	cells := strings.Repeat(cell+",", n)
+	row := "[" + cells[:len(cells)-1] + "]"
+	rs := strings.Repeat(row+",", 9)
+	return "[" + rs[:len(rs)-1] + "]"
+}
+
+func TestRepair(t *testing.T) {
+	f := filepath.Join(t.TempDir(), "state.json")
+	b := NewBoard()
+	if err := b.Load(puzzleFile); err != nil {
+		t.Fatalf("error loading puzzle file: %s", err)
+	}
+	b[0][0].Number = 15
+	b[1][1].Number, b[1][1].Given = 3, true // clashes with [01] in the column and box
+	b[1][2].Marks = []int{0, 4}
+	if err := b.Save(f); err != nil {
+		t.Fatalf("error saving puzzle: %s", err)
+	}
+
+	// still loaded, with the problems listed
+	r := NewBoard()
+	err := r.Load(f)
+	var pe *PuzzleError
+	if !errors.As(err, &pe) || len(pe.Problems) != 4 {
+		t.Fatalf("Load = %v; want 4 problems", err)
+	}
+	if r[0][0].Number != 15 {
+		t.Errorf("[00] = %d; want 15", r[0][0].Number)
+	}
+
+	changed := r.Repair()
+	want := []Cell{{row: 0, col: 0}, {row: 1, col: 2}, {row: 1, col: 1}}
+	if fmt.Sprint(changed) != fmt.Sprint(want) {
+		t.Errorf("Repair changed %v; want %v", changed, want)
+	}
+	if err := r.Check(false); err != nil {
+		t.Errorf("repaired puzzle: %s", err)
+	}
+	if r[0][0].Number != 0 || r[1][1].Number != 0 || r[0][1].Number != 3 || len(r[1][2].Marks) != 1 {
+		t.Errorf("repaired board:\n%s", r.Grid())
+	}
+}
+
+func TestRedundant(t *testing.T) {
+	p := Generate(GenOptions{Seed: 5, Minimal: true})
+	b := p.Board
+	redundant, err := b.Redundant()
+	if err != nil || len(redundant) != 0 {
+		t.Fatalf("minimal puzzle has redundant clues %v, %v:\n%s", redundant, err, b.Grid())
+	}
+
+	// a clue from the solution added is not needed
+	sol, err := b.Solve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var added Cell
+	for _, c := range sol[4] {
+		if b[4][c.col].Number == 0 {
+			added = c
+			break
+		}
+	}
+	b[added.row][added.col].Number = added.Number
+	b[added.row][added.col].Given = true
+	redundant, err = b.Redundant()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, c := range redundant {
+		found = found || sameCell(c, added)
+	}
+	if !found {
+		t.Errorf("clue %s not redundant: %v", added, redundant)
+	}
+
+	empty := NewBoard()
+	if _, err := empty.Redundant(); err != ErrMultipleSolutions {
+		t.Errorf("empty board: %v", err)
+	}
+}
@@ -0,0 +1,514 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/kamhlos/dokusu"
+	"github.com/kamhlos/dokusu/tui"
+)
+
+// exit codes of the commands
+const (
+	exitOK    = 0 // done; the puzzle is valid, solved or graded
+	exitFail  = 1 // the puzzle is invalid or cannot be solved
+	exitUsage = 2 // bad arguments, or files that cannot be read or written
+)
+
+// cli runs dokusu's commands with their input and output
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// a command and what it does
+type command struct {
+	name    string
+	summary string
+	run     func(c *cli, args []string) int
+}
+
+var commands = []command{
+	{"play", "play a puzzle, or resume the saved game", (*cli).play},
+	{"solve", "write a puzzle's solution", (*cli).solve},
+	{"grade", "rate a puzzle by the techniques it needs", (*cli).grade},
+	{"validate", "check a puzzle has no clashes and a single solution", (*cli).validate},
+	{"convert", "write a puzzle in another format", (*cli).convert},
+	{"generate", "write a new random puzzle", (*cli).generate},
+	{"report", "solve and grade a file of puzzles, one per line", (*cli).report},
+}
+
+// runCommand runs a command on the standard input and output;
+// returns the exit code
+func runCommand(args []string) int {
+	c := &cli{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+	return c.run(args)
+}
+
+func (c *cli) run(args []string) int {
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		c.usage(c.stdout)
+		return exitOK
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(c, args[1:])
+		}
+	}
+	fmt.Fprintf(c.stderr, "dokusu: unknown command %q\n\n", args[0])
+	c.usage(c.stderr)
+	return exitUsage
+}
+
+func (c *cli) usage(w io.Writer) {
+	fmt.Fprintf(w, "usage: dokusu [command] [flags]\n\n")
+	fmt.Fprintf(w, "With no command, a menu offers to play %s or a random puzzle, or resume the saved game.\n\n", puzzleFile)
+	fmt.Fprintf(w, "commands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(w, "\nPuzzles are read as JSON boards, save files or text: 81 digits, row by row,\n")
+	fmt.Fprintf(w, "with 0 or . for empty cells. Run \"dokusu <command> -h\" for a command's flags.\n\n")
+	fmt.Fprintf(w, "exit codes: %d done, %d invalid or unsolvable puzzle, %d bad usage or files\n", exitOK, exitFail, exitUsage)
+}
+
+// flags of a command; all write a result, most read a puzzle
+type cmdFlags struct {
+	*flag.FlagSet
+	in  string
+	out string
+}
+
+func (c *cli) flags(name string) *cmdFlags {
+	f := c.outFlags(name)
+	f.StringVar(&f.in, "in", "-", "puzzle file, - for standard input")
+	return f
+}
+
+// flags of a command that reads no puzzle
+func (c *cli) outFlags(name string) *cmdFlags {
+	f := &cmdFlags{FlagSet: flag.NewFlagSet("dokusu "+name, flag.ContinueOnError)}
+	f.SetOutput(c.stderr)
+	f.StringVar(&f.out, "out", "-", "output file, - for standard output")
+	return f
+}
+
+// parse a command's arguments; ok is false if the command
+// should exit with the code returned
+func (f *cmdFlags) parse(args []string) (int, bool) {
+	err := f.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK, false
+	}
+	if err != nil {
+		return exitUsage, false
+	}
+	if f.NArg() > 0 {
+		fmt.Fprintf(f.Output(), "%s: unexpected arguments %v\n", f.Name(), f.Args())
+		return exitUsage, false
+	}
+	return 0, true
+}
+
+// print an error of a command and return its exit code
+func (c *cli) fail(name string, code int, err error) int {
+	fmt.Fprintf(c.stderr, "dokusu %s: %s\n", name, err)
+	return code
+}
+
+// read the puzzle of a file, - for the standard input
+func (c *cli) read(in string) (dokusu.Board, error) {
+	var j []byte
+	var err error
+	if in == "-" {
+		j, err = ioutil.ReadAll(c.stdin)
+	} else {
+		j, err = ioutil.ReadFile(in)
+	}
+	if err != nil {
+		return dokusu.Board{}, err
+	}
+
+	b, err := dokusu.Decode(j)
+	if err != nil {
+		return dokusu.Board{}, fmt.Errorf("%s: %w", in, err)
+	}
+	return b, nil
+}
+
+// readCode is the exit code of an error reading a puzzle:
+// invalid puzzles fail, unreadable ones are bad arguments
+func readCode(err error) int {
+	var invalid *dokusu.PuzzleError
+	if errors.As(err, &invalid) {
+		return exitFail
+	}
+	return exitUsage
+}
+
+// write to a file, - for the standard output
+func (c *cli) write(out string, data []byte) error {
+	if out == "-" {
+		_, err := c.stdout.Write(data)
+		return err
+	}
+	return ioutil.WriteFile(out, data, 0644)
+}
+
+func (c *cli) play(args []string) int {
+	f := flag.NewFlagSet("dokusu play", flag.ContinueOnError)
+	f.SetOutput(c.stderr)
+	in := f.String("in", puzzleFile, "puzzle file")
+	resume := f.Bool("resume", false, "resume the saved game")
+	state := f.String("state", tui.StateFile, "file the game is saved to")
+	err := f.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		return exitUsage
+	}
+	if *in == "-" {
+		return c.fail("play", exitUsage, errors.New("moves are read from the standard input, the puzzle cannot be"))
+	}
+
+	tui.StateFile = *state
+	if *resume {
+		err = tui.Resume()
+	} else {
+		err = tui.PlayPuzzle(*in)
+	}
+	if err != nil {
+		return c.fail("play", exitUsage, err)
+	}
+	return exitOK
+}
+
+func (c *cli) solve(args []string) int {
+	f := c.flags("solve")
+	format := f.String("format", "line", "output format: json, line or grid")
+	unique := f.Bool("unique", false, "fail if the puzzle has more than one solution")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	ft, err := dokusu.ParseFormat(*format)
+	if err != nil {
+		return c.fail("solve", exitUsage, err)
+	}
+	b, err := c.read(f.in)
+	if err != nil {
+		return c.fail("solve", readCode(err), err)
+	}
+
+	s, err := b.Solve()
+	if err != nil {
+		return c.fail("solve", exitFail, err)
+	}
+	if *unique && b.CountSolutions(2) > 1 {
+		return c.fail("solve", exitFail, errors.New("puzzle has more than one solution"))
+	}
+
+	out, err := s.Format(ft)
+	if err == nil {
+		err = c.write(f.out, out)
+	}
+	if err != nil {
+		return c.fail("solve", exitUsage, err)
+	}
+	return exitOK
+}
+
+func (c *cli) grade(args []string) int {
+	f := c.flags("grade")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	b, err := c.read(f.in)
+	if err != nil {
+		return c.fail("grade", readCode(err), err)
+	}
+	if _, err := b.Solve(); err != nil {
+		return c.fail("grade", exitFail, err)
+	}
+
+	r := b.Grade()
+	out := r.String() + "\n"
+	for _, t := range r.Used() {
+		out += fmt.Sprintf("\t%s: %d\n", t, r.Techniques[t])
+	}
+
+	if err := c.write(f.out, []byte(out)); err != nil {
+		return c.fail("grade", exitUsage, err)
+	}
+	return exitOK
+}
+
+func (c *cli) validate(args []string) int {
+	f := c.flags("validate")
+	unique := f.Bool("unique", true, "require a single solution")
+	minimal := f.Bool("minimal", false, "require every clue to be needed for a single solution")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	b, err := c.read(f.in)
+	if err == nil {
+		err = b.Check(*unique || *minimal)
+	}
+	var invalid *dokusu.PuzzleError
+	if err != nil && !errors.As(err, &invalid) {
+		return c.fail("validate", exitUsage, err)
+	}
+
+	var problems []string
+	if invalid != nil {
+		for _, p := range invalid.Problems {
+			problems = append(problems, p.Error())
+		}
+	} else if *minimal {
+		redundant, err := b.Redundant()
+		if err != nil {
+			return c.fail("validate", exitFail, err)
+		}
+		for _, cell := range redundant {
+			problems = append(problems, fmt.Sprintf("cell %s: clue %d is redundant", cell, cell.Number))
+		}
+	}
+
+	out := "valid\n"
+	code := exitOK
+	if len(problems) > 0 {
+		out = strings.Join(problems, "\n") + "\n"
+		code = exitFail
+	}
+	if err := c.write(f.out, []byte(out)); err != nil {
+		return c.fail("validate", exitUsage, err)
+	}
+	return code
+}
+
+func (c *cli) convert(args []string) int {
+	f := c.flags("convert")
+	format := f.String("format", "line", "output format: json, line or grid")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	ft, err := dokusu.ParseFormat(*format)
+	if err != nil {
+		return c.fail("convert", exitUsage, err)
+	}
+	b, err := c.read(f.in)
+	if err != nil {
+		return c.fail("convert", readCode(err), err)
+	}
+
+	out, err := b.Format(ft)
+	if err == nil {
+		err = c.write(f.out, out)
+	}
+	if err != nil {
+		return c.fail("convert", exitUsage, err)
+	}
+	return exitOK
+}
+
+func (c *cli) generate(args []string) int {
+	f := c.outFlags("generate")
+	format := f.String("format", "line", "output format: json, line or grid")
+	clues := f.Int("clues", 0, "clues to stop at, 0 for as few as possible")
+	symmetry := f.String("symmetry", "none", "symmetry of the clues: none, rotational, mirror or diagonal")
+	timeout := f.Duration("timeout", 0, "stop removing clues after this long, 0 for no limit")
+	tier := f.String("tier", "", "difficulty: easy, medium, hard, expert or diabolical")
+	technique := f.String("technique", "", "technique needed, and none harder, e.g. x-wing")
+	limit := f.Duration("limit", time.Minute, "time to find a puzzle of the tier or technique, or each of a batch")
+	seed := f.Int64("seed", 0, "seed of the puzzle, 0 for a random one")
+	daily := f.Bool("daily", false, "the puzzle of the day, the same for everyone")
+	minimal := f.Bool("minimal", false, "remove clues until every one left is needed")
+	count := f.Int("count", 1, "puzzles to generate; more than one are written one per line")
+	workers := f.Int("workers", 0, "puzzles generated at once, 0 for one per CPU")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	ft, err := dokusu.ParseFormat(*format)
+	if err != nil {
+		return c.fail("generate", exitUsage, err)
+	}
+	sym, err := dokusu.ParseSymmetry(*symmetry)
+	if err != nil {
+		return c.fail("generate", exitUsage, err)
+	}
+	if *clues < 0 || *clues > 81 {
+		return c.fail("generate", exitUsage, fmt.Errorf("clues must be from 0 to 81, not %d", *clues))
+	}
+	if *count < 1 {
+		return c.fail("generate", exitUsage, fmt.Errorf("count must be at least 1, not %d", *count))
+	}
+
+	opts := dokusu.GenOptions{Clues: *clues, Symmetry: sym, Timeout: *timeout, Seed: *seed, Minimal: *minimal}
+	if *daily {
+		opts.Seed = dokusu.DailySeed(time.Now())
+	}
+	var band *dokusu.Band
+	switch {
+	case *tier != "" && *technique != "":
+		return c.fail("generate", exitUsage, errors.New("-tier and -technique cannot be used together"))
+	case *tier != "":
+		t, err := dokusu.ParseTier(*tier)
+		if err != nil {
+			return c.fail("generate", exitUsage, err)
+		}
+		b := dokusu.TierBand(t)
+		band = &b
+	case *technique != "":
+		t, err := dokusu.ParseTechnique(*technique)
+		if err != nil {
+			return c.fail("generate", exitUsage, err)
+		}
+		b := dokusu.TechniqueBand(t)
+		band = &b
+	}
+
+	if *count > 1 {
+		if ft != dokusu.LineFormat {
+			return c.fail("generate", exitUsage, errors.New("puzzles of a batch are written one per line"))
+		}
+		if opts.Seed == 0 {
+			opts.Seed = time.Now().UnixNano()
+		}
+		batch := dokusu.BatchOptions{GenOptions: opts, Count: *count, Workers: *workers, Band: band, PuzzleTimeout: *limit}
+		return c.generateBatch(batch, f.out)
+	}
+
+	var p dokusu.Puzzle
+	if band == nil {
+		p = dokusu.Generate(opts)
+		fmt.Fprintf(c.stderr, "seed %d\n", p.Seed)
+	} else {
+		var r dokusu.Rating
+		p, r, err = dokusu.GenerateRated(opts, *band, *limit)
+		if err != nil {
+			return c.fail("generate", exitFail, err)
+		}
+		fmt.Fprintf(c.stderr, "seed %d, rated %s\n", p.Seed, r)
+	}
+
+	out, err := p.Board.Format(ft)
+	if err == nil {
+		err = c.write(f.out, out)
+	}
+	if err != nil {
+		return c.fail("generate", exitUsage, err)
+	}
+	return exitOK
+}
+
+// generate a batch of puzzles, writing each one as it is ready
+// with the seed that generates it again; stops early if interrupted
+func (c *cli) generateBatch(opts dokusu.BatchOptions, out string) int {
+	w := c.stdout
+	if out != "-" {
+		f, err := os.Create(out)
+		if err != nil {
+			return c.fail("generate", exitUsage, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	written := 0
+	gaveUp, err := dokusu.GenerateBatch(ctx, opts, func(p dokusu.Puzzle) error {
+		if _, err := fmt.Fprintf(w, "%s\t%d\n", p.Board.Line(), p.Seed); err != nil {
+			return err
+		}
+		written++
+		return nil
+	})
+	fmt.Fprintf(c.stderr, "%d puzzles, seed %d, %d given up after %s\n", written, opts.Seed, gaveUp, opts.PuzzleTimeout)
+	if err != nil {
+		return c.fail("generate", exitFail, err)
+	}
+	return exitOK
+}
+
+// the columns of a report, separated by tabs
+const reportHeader = "line\tpuzzle\tsolution\tuniqueness\trating\ttechniques\ttime\n"
+
+func (c *cli) report(args []string) int {
+	f := c.flags("report")
+	workers := f.Int("workers", 0, "puzzles solved at once, 0 for one per CPU")
+	if code, ok := f.parse(args); !ok {
+		return code
+	}
+	in := c.stdin
+	if f.in != "-" {
+		r, err := os.Open(f.in)
+		if err != nil {
+			return c.fail("report", exitUsage, err)
+		}
+		defer r.Close()
+		in = r
+	}
+	w := c.stdout
+	if f.out != "-" {
+		out, err := os.Create(f.out)
+		if err != nil {
+			return c.fail("report", exitUsage, err)
+		}
+		defer out.Close()
+		w = out
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if _, err := io.WriteString(w, reportHeader); err != nil {
+		return c.fail("report", exitUsage, err)
+	}
+	puzzles, failed := 0, 0
+	err := dokusu.SolveAll(ctx, in, *workers, func(r dokusu.Report) error {
+		puzzles++
+		if r.Err != nil || r.Unique != dokusu.UniqueSolution {
+			failed++
+		}
+		_, err := io.WriteString(w, reportLine(r))
+		return err
+	})
+	fmt.Fprintf(c.stderr, "%d puzzles, %d without a single solution\n", puzzles, failed)
+	if err != nil {
+		return c.fail("report", exitUsage, err)
+	}
+	if failed > 0 {
+		return exitFail
+	}
+	return exitOK
+}
+
+// reportLine is a puzzle's line of a report, see reportHeader
+func reportLine(r dokusu.Report) string {
+	if r.Err != nil {
+		return fmt.Sprintf("%d\t-\t-\tinvalid: %s\t-\t-\t-\n", r.Line, r.Err)
+	}
+	solution, rating, techniques := "-", "-", "-"
+	if r.Unique != dokusu.NoSolution {
+		solution = r.Solution.Line()
+	}
+	if r.Unique == dokusu.UniqueSolution {
+		rating = r.Rating.String()
+		var used []string
+		for _, t := range r.Rating.Used() {
+			used = append(used, fmt.Sprintf("%s:%d", t, r.Rating.Techniques[t]))
+		}
+		techniques = strings.Join(used, ",")
+	}
+	return fmt.Sprintf("%d\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Line, r.Puzzle.Line(), solution, r.Unique, rating, techniques, r.Time)
+}
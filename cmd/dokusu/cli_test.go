@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// run a command on an input; returns its exit code and output
+func runCLI(args []string, in string) (int, string, string) {
+	var out, errs bytes.Buffer
+	c := &cli{stdin: strings.NewReader(in), stdout: &out, stderr: &errs}
+	code := c.run(args)
+	return code, out.String(), errs.String()
+}
+
+// puzzles from the library's tests: an easy one and a hard one
+const (
+	easy = "003020600900305001001806400008102900700000008006708200002609500800203009005010300"
+	hard = "8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4.."
+)
+
+func TestCLI(t *testing.T) {
+	tests := []struct {
+		args []string
+		in   string
+		code int
+		out  string // part of the output expected
+	}{
+		{[]string{"solve"}, easy, exitOK, "483921657967345821251876493548132976729564138136798245372689514814253769695417382\n"},
+		{[]string{"solve", "-format", "grid"}, easy, exitOK, "4 8 3 | 9 2 1 | 6 5 7\n"},
+		{[]string{"solve", "-unique"}, strings.Repeat(".", 81), exitFail, ""},
+		{[]string{"solve"}, "12", exitUsage, ""},
+		{[]string{"solve"}, "11" + strings.Repeat(".", 79), exitFail, ""},
+		{[]string{"solve", "-format", "csv"}, easy, exitUsage, ""},
+		{[]string{"grade"}, easy, exitOK, "easy"},
+		{[]string{"validate"}, easy, exitOK, "valid\n"},
+		{[]string{"validate"}, "11" + strings.Repeat(".", 79), exitFail, "cell [00]"},
+		{[]string{"validate"}, strings.Repeat(".", 81), exitFail, "more than one solution"},
+		{[]string{"validate"}, "[[]]", exitUsage, ""},
+		{[]string{"validate", "-unique=false"}, strings.Repeat(".", 81), exitOK, "valid"},
+		{[]string{"validate", "-minimal"}, easy, exitFail, "is redundant"},
+		{[]string{"validate", "-minimal", "-unique=false"}, strings.Repeat(".", 81), exitFail, "more than one solution"},
+		{[]string{"convert", "-format", "json"}, easy, exitOK, `"Given": true`},
+		{[]string{"generate", "-symmetry", "spiral"}, "", exitUsage, ""},
+		{[]string{"generate", "-clues", "82"}, "", exitUsage, ""},
+		{[]string{"generate", "-in", "puzzle.json"}, "", exitUsage, ""},
+		{[]string{"generate", "-tier", "easy", "-technique", "x-wing"}, "", exitUsage, ""},
+		{[]string{"generate", "-tier", "trivial"}, "", exitUsage, ""},
+		{[]string{"generate", "-technique", "guessing"}, "", exitUsage, ""},
+		{[]string{"generate", "-count", "3", "-format", "grid"}, "", exitUsage, ""},
+		{[]string{"generate", "-count", "0"}, "", exitUsage, ""},
+		{[]string{"generate", "-count", "-2"}, "", exitUsage, ""},
+		{[]string{"help"}, "", exitOK, "commands:"},
+		{[]string{"bogus"}, "", exitUsage, ""},
+	}
+
+	for _, tt := range tests {
+		code, out, errs := runCLI(tt.args, tt.in)
+		if code != tt.code {
+			t.Errorf("%v: exit code %d, want %d (%s)", tt.args, code, tt.code, errs)
+		}
+		if !strings.Contains(out, tt.out) {
+			t.Errorf("%v: output %q, want %q in it", tt.args, out, tt.out)
+		}
+	}
+}
+
+func TestConvertJSON(t *testing.T) {
+	code, j, _ := runCLI([]string{"convert", "-format", "json"}, hard)
+	if code != exitOK {
+		t.Fatalf("convert to json failed: %d", code)
+	}
+	code, line, _ := runCLI([]string{"convert"}, j)
+	if code != exitOK || line != hard+"\n" {
+		t.Errorf("json read back as %q", line)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	code, line, errs := runCLI([]string{"generate", "-clues", "30", "-symmetry", "rotational"}, "")
+	if code != exitOK || len(line) != 82 {
+		t.Fatalf("generate: %d %q %s", code, line, errs)
+	}
+	if n := 81 - strings.Count(line, "."); n < 30 {
+		t.Errorf("puzzle has %d clues, want at least 30", n)
+	}
+	if code, out, _ := runCLI([]string{"validate"}, line); code != exitOK {
+		t.Errorf("generated puzzle %q: %s", line, out)
+	}
+}
+
+func TestGenerateTier(t *testing.T) {
+	code, line, errs := runCLI([]string{"generate", "-tier", "medium", "-limit", "10s"}, "")
+	if code != exitOK || !strings.Contains(errs, "rated medium") {
+		t.Fatalf("generate: %d %q %s", code, line, errs)
+	}
+	if code, out, _ := runCLI([]string{"grade"}, line); code != exitOK || !strings.HasPrefix(out, "medium") {
+		t.Errorf("generated puzzle %q graded %s", line, out)
+	}
+}
+
+func TestGenerateSeed(t *testing.T) {
+	_, line, errs := runCLI([]string{"generate"}, "")
+	var seed string
+	if _, err := fmt.Sscanf(errs, "seed %s\n", &seed); err != nil {
+		t.Fatalf("no seed in %q", errs)
+	}
+	if _, again, _ := runCLI([]string{"generate", "-seed", seed}, ""); again != line {
+		t.Errorf("seed %s generated %q and %q", seed, line, again)
+	}
+
+	_, daily, errs := runCLI([]string{"generate", "-daily"}, "")
+	if _, again, _ := runCLI([]string{"generate", "-daily"}, ""); again != daily {
+		t.Errorf("daily puzzles %q and %q", daily, again)
+	}
+	if !strings.HasPrefix(errs, "seed "+time.Now().UTC().Format("20060102")) {
+		t.Errorf("daily seed %q", errs)
+	}
+}
+
+func TestGenerateMinimal(t *testing.T) {
+	code, line, errs := runCLI([]string{"generate", "-minimal", "-symmetry", "mirror"}, "")
+	if code != exitOK {
+		t.Fatalf("generate: %d %s", code, errs)
+	}
+	if code, out, _ := runCLI([]string{"validate", "-minimal"}, line); code != exitOK {
+		t.Errorf("generated puzzle %q not minimal: %s", line, out)
+	}
+}
+
+func TestGenerateBatch(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "batch.txt")
+	code, _, errs := runCLI([]string{"generate", "-count", "5", "-workers", "2", "-out", out}, "")
+	if code != exitOK || !strings.HasPrefix(errs, "5 puzzles") {
+		t.Fatalf("generate: %d %s", code, errs)
+	}
+	j, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(j)), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("%d puzzles written: %q", len(lines), j)
+	}
+	for _, line := range lines {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 2 {
+			t.Fatalf("line %q; want a puzzle and its seed", line)
+		}
+		if code, out, _ := runCLI([]string{"validate"}, fields[0]); code != exitOK {
+			t.Errorf("puzzle %q: %s", fields[0], out)
+		}
+		if _, out, _ := runCLI([]string{"generate", "-seed", fields[1]}, ""); out != fields[0]+"\n" {
+			t.Errorf("seed %s generated %q; want %q", fields[1], out, fields[0])
+		}
+	}
+
+	// the seeds are skipped when reporting
+	if code, out, errs := runCLI([]string{"report", "-in", out}, ""); code != exitOK || strings.Contains(out, "invalid") {
+		t.Errorf("report: %d %s\n%s", code, errs, out)
+	}
+}
+
+func TestReport(t *testing.T) {
+	in := easy + "\n" + hard + "\n\n12\n"
+	code, out, errs := runCLI([]string{"report", "-workers", "2"}, in)
+	if code != exitFail || errs != "3 puzzles, 1 without a single solution\n" {
+		t.Errorf("report: %d %s", code, errs)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 4 || lines[0]+"\n" != reportHeader {
+		t.Fatalf("report:\n%s", out)
+	}
+	wants := []string{
+		"1\t" + strings.ReplaceAll(easy, "0", ".") + "\t483921657967345821251876493548132976729564138136798245372689514814253769695417382\tunique\teasy",
+		"2\t" + hard + "\t",
+		"4\t-\t-\tinvalid: ",
+	}
+	for i, want := range wants {
+		if !strings.HasPrefix(lines[i+1], want) {
+			t.Errorf("line %q; want it to start with %q", lines[i+1], want)
+		}
+	}
+
+	if code, _, _ := runCLI([]string{"report"}, easy); code != exitOK {
+		t.Errorf("report of a valid puzzle: exit code %d", code)
+	}
+}
@@ -0,0 +1,20 @@
+// Command dokusu plays sudoku puzzles in the terminal, or solves,
+// grades, validates and converts them from scripts; see the usage
+// with "dokusu help".
+package main
+
+import (
+	"os"
+
+	"github.com/kamhlos/dokusu/tui"
+)
+
+// puzzleFile is where games are loaded from
+var puzzleFile = "puzzle.json"
+
+func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+	tui.Menu(puzzleFile)
+}
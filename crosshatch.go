@@ -0,0 +1,45 @@
+package dokusu
+
+// CrossHatch highlights a number on the board: rows, columns and boxes
+// already holding it are selected, empty cells it still fits in are
+// candid, and cells where it is forced blink. Returns the forced cells.
+func (b *Board) CrossHatch(n int) []Cell {
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			if b[row][col].Number == n {
+				b.selectCells(row, col)
+			}
+		}
+	}
+
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			if b[row][col].Number == 0 && !b[row][col].Selected {
+				b[row][col].Candid = true
+			}
+		}
+	}
+
+	// forced where it is the only open cell of a unit missing the number;
+	// boxes first, as when cross-hatching on paper
+	var forced []Cell
+	for i := 0; i < 27; i++ {
+		u := units[(18+i)%27]
+		if b.placed(u, n) {
+			continue
+		}
+		var open []Cell
+		for _, c := range u.cells {
+			if b[c.row][c.col].Candid {
+				open = append(open, c)
+			}
+		}
+		if len(open) != 1 || b[open[0].row][open[0].col].Blink {
+			continue
+		}
+		b[open[0].row][open[0].col].Blink = true
+		forced = append(forced, open[0])
+	}
+
+	return forced
+}
@@ -0,0 +1,54 @@
+package dokusu
+
+import (
+	"testing"
+)
+
+func TestCrossHatch(t *testing.T) {
+	b := NewBoard()
+	err := b.Load(puzzleFile)
+	if err != nil {
+		t.Fatalf("error loading puzzle file: %s", err)
+	}
+
+	for n := 1; n < 10; n++ {
+		b.Clear()
+		forced := b.CrossHatch(n)
+		t.Logf("number %d forced in %v", n, forced)
+
+		for row := 0; row < 9; row++ {
+			for col := 0; col < 9; col++ {
+				c := b[row][col]
+				fits := c.Number == 0 && b.checkNum(n, row, col) == nil
+				if c.Candid != fits {
+					t.Errorf("number %d: [%d%d] candid = %v; want %v", n, row, col, c.Candid, fits)
+				}
+				if c.Number == 0 && !fits && !c.Selected {
+					t.Errorf("number %d: [%d%d] not selected", n, row, col)
+				}
+			}
+		}
+
+		// forced cells are the hidden singles of this number
+		l := b
+		l.MarkCells()
+		for _, c := range forced {
+			if !b[c.row][c.col].Blink {
+				t.Errorf("number %d: forced %s not blinking", n, c)
+			}
+			single := false
+			for _, u := range []Unit{rowUnit(c.row), colUnit(c.col), boxUnit(c.row, c.col)} {
+				if len(l.positions(u, n)) == 1 {
+					single = true
+				}
+			}
+			if !single {
+				t.Errorf("number %d: %s is not forced", n, c)
+			}
+		}
+	}
+	b.Clear()
+	b.CrossHatch(5)
+	t.Logf("\n%s", b.Grid())
+	b.Clear()
+}
@@ -0,0 +1,20 @@
+// Package dokusu is a sudoku library: boards and their validation,
+// a solver, a logical solver that finds the steps a player would
+// take, a grader rating puzzles by the techniques they need, a
+// generator of puzzles, and games with moves that can be undone
+// and saved.
+//
+// A Board is 9 rows of 9 cells, numbered 0 to 8; empty cells are 0.
+// Boards are values, and no function keeps state between calls,
+// so different boards can be worked on at once, as GenerateBatch
+// does; each generator draws from its own seeded source.
+//
+//	b, err := dokusu.ParseText(puzzle) // 81 digits, 0 or . for empty cells
+//	...
+//	solution, err := b.Solve()
+//	rating := b.Grade()
+//	step, ok := b.Hint()
+//
+// The dokusu command in cmd/dokusu plays puzzles in the terminal,
+// using the tui package to print boards.
+package dokusu
@@ -1,31 +1,41 @@
-package main
+package dokusu
 
 import (
 	// "fmt"
+	"errors"
+	"math/rand"
+	"path/filepath"
 	"testing"
 )
 
+// puzzleFile is the puzzle tests load
+var puzzleFile = "puzzle.json"
+
+// testRand is a random source that is the same on every run
+func testRand() *rand.Rand {
+	return rand.New(rand.NewSource(1))
+}
+
 func TestPrintCell(t *testing.T) {
-	debug = false
-	b := board()
+	b := NewBoard()
 	// for i := 0; i < 9; i++ {
 	// 	t.Logf("%d mod 3 equals: %#+v", i, i%3)
 	// }
-	b.gen3boxes()
+	b.gen3boxes(testRand())
 	b.selectCells(5, 8)
-	b.print()
+	t.Logf("\n%s", b.Grid())
 }
 
 func TestRandRow(t *testing.T) {
-	b := board()
+	b := NewBoard()
 	ints := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
-	ints = shuffle(ints)
+	ints = shuffle(testRand(), ints)
 
 	// populate a row
 	for col := 0; col < 9; col++ {
 		b[0][col].Number = ints[col]
 	}
-	b.print()
+	t.Logf("\n%s", b.Grid())
 
 	// check row
 	for col := 0; col < 9; col++ {
@@ -33,336 +43,272 @@ func TestRandRow(t *testing.T) {
 		if got > 9 || got < 1 { // this never happens
 			t.Fail()
 			t.Logf("not a valid number: %d", got)
-			b[0][col].invalid = true
+			b[0][col].Invalid = true
 		}
 	}
-	b.print()
+	t.Logf("\n%s", b.Grid())
 }
 
 func TestRandColumn(t *testing.T) {
-	b := board()
+	b := NewBoard()
 	ints := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
-	ints = shuffle(ints)
+	ints = shuffle(testRand(), ints)
 
 	// populate column 5
 	column := 5
 	for row := 0; row < 9; row++ {
 		b[row][column].Number = ints[row]
 	}
-	b.print()
+	t.Logf("\n%s", b.Grid())
 }
 
 func TestGenBox(t *testing.T) {
-	b := board()
+	b := NewBoard()
 	ints := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
-	ints = shuffle(ints)
+	ints = shuffle(testRand(), ints)
 
 	// populate first box
+	r := testRand()
 	cell := Cell{row: 0, col: 0}
-	b.genBox(cell)
+	b.genBox(r, cell)
 	// populate second box
 	cell = Cell{row: 3, col: 3}
-	b.genBox(cell)
+	b.genBox(r, cell)
 	// populate third box
 	cell = Cell{row: 6, col: 6}
-	b.genBox(cell)
-
-	b.print()
-}
-
-func TestFillBox(t *testing.T) {
-	debug = true
-	b := board()
-	b.gen3boxes()
-	// b.print()
-
-	// try filling second starting from cell [03]
-	c := Cell{row: 0, col: 3}
-	b.fillBox(c, 0, []int{})
-	b.print()
-
-	if b[c.row+2][c.col+2].Number > 0 {
-		c := Cell{row: 0, col: 6}
-		b.fillBox(c, 0, []int{})
-		b.print()
-	}
-
-	if b[c.row+2][c.col+2].Number > 0 {
-		c := Cell{row: 3, col: 6}
-		b.fillBox(c, 0, []int{})
-		b.print()
-	}
+	b.genBox(r, cell)
 
-	if b[c.row+2][c.col+2].Number > 0 {
-		c := Cell{row: 3, col: 0}
-		b.fillBox(c, 0, []int{})
-		b.print()
-	}
+	t.Logf("\n%s", b.Grid())
 }
 
 func TestComplete(t *testing.T) {
-	debug = false
-	b := board()
-	b.gen3boxes()
-	b.markCells()
-
-	for i := 0; i < 10; i++ {
-		t.Logf("------------- START ATTEMPT #%d ----------------", i)
-		if b.setBox(b[0][3], i) {
-			t.Logf("finished at retry #%d", i)
-			break
-		} else {
-			b.print()
-			b.clearBox(b[0][3])
-			
-		}
+	b := NewBoard()
+	b.gen3boxes(testRand())
 
+	solved, err := b.Solve()
+	if err != nil {
+		t.Fatalf("cannot complete board: %s", err)
 	}
-	b.print()
-}
+	t.Logf("\n%s", solved.Grid())
 
-func TestGenCell(t *testing.T) {
-	b := board()
-	b.gen3boxes()
-	b.print()
-	// proceed one cell at a time
-	for row := 0; row < 9; row++ {
-		for col := 0; col < 9; col++ {
-			c := b[row][col]
-			if b[row][col].Number == 0 {
-				used := b.findUsed(c)
-				free := b.findFree(c, used)
-				t.Logf("free numbers for [%d%d]: %#v", row, col, free)
-				// set cell's number with the first free
-				b[row][col].Number = free[0]
-				b.print()
-				t.Logf("set number %d for [%d%d]\n", free[0], row, col)
-				if col == 8 {
-					t.Logf("---------- row %d complete\n", row)
-				}
-				continue
-			}
-			t.Logf("number %d already set in [%d%d]\n", b[row][col].Number, row, col)
-			if col == 8 {
-				t.Logf("+++ column %d complete\n", col)
-			}
-		}
-		if row == 8 {
-			t.Logf("---------- row %d complete\n", row)
-		}
+	if !solved.IsComplete() {
+		t.Error("board not complete")
 	}
-	b.print()
-}
-
-func TestFindUsed(t *testing.T) {
-	b := board()
-	b.gen3boxes()
-
-	// find used numbers (not available) for this cell
-	c := b[5][2]
-	used := b.findUsed(c)
-	b.print()
-	t.Logf("used numbers (not available) for [%d%d], %#+v", c.row, c.col, used)
 }
 
-func TestFindFree(t *testing.T) {
-	b := board()
-	b.gen3boxes()
-
-	// cells to check for free (available) numbers
-	var tests = []struct {
-		row  int
-		col  int
-		want int
-	}{
-		{0, 0, 0},
-		{1, 0, 0},
-		{2, 0, 0},
-		{0, 1, 0},
-		{1, 2, 0},
-		{2, 3, 9},
-		{3, 3, 0},
-		{3, 4, 0},
-		{3, 5, 0},
-		{3, 6, 9},
-		{4, 7, 9},
-		{5, 0, 9},
-		{6, 1, 9},
-		{7, 1, 9},
-		{8, 1, 9},
-		{8, 7, 0},
-		{8, 8, 0},
-		{5, 5, 0},
-		{8, 6, 0},
+// sameConflict reports if two conflicts are the same, or both nil
+func sameConflict(e1, e2 *ConflictError) bool {
+	if e1 == nil || e2 == nil {
+		return e1 == e2
 	}
+	return e1.Kind == e2.Kind && e1.Number == e2.Number &&
+		sameCell(e1.Cell, e2.Cell) && sameCell(e1.At, e2.At)
+}
 
-	for _, test := range tests {
-		t.Logf("testing cell [%d%d]", test.row, test.col)
-		c := b[test.row][test.col]
-		used := b.findUsed(c)
-		free := b.findFree(c, used)
-		if len(used)+len(free) != test.want {
-			t.Errorf("used+free equals to %d", len(used)+len(free))
-		}
-		for i := 0; i < len(used); i++ {
-			for j := 0; j < len(free); j++ {
-				if used[i] == free[j] {
-					t.Errorf("used %d found in free", used[i])
-				}
-			}
-		}
-	}
+// conflict with number n found in cell [row col] of a unit
+func conflict(kind UnitKind, n, row, col int) *ConflictError {
+	return &ConflictError{Kind: kind, Number: n, Cell: Cell{row: row, col: col}}
 }
 
 func TestCheckRow(t *testing.T) {
-	b := board()
+	b := NewBoard()
 	// numbers to check against the current state of the board
 	var tests = []struct {
 		num  int
 		row  int
-		want interface{}
+		want *ConflictError
 	}{
-		{1, 0, "number 1 found in cell [02]"},
-		{5, 0, "number 5 found in cell [00]"},
+		{1, 0, conflict(RowUnit, 1, 0, 2)},
+		{5, 0, conflict(RowUnit, 5, 0, 0)},
 		{4, 0, nil},
 		{4, 8, nil},
 		{3, 6, nil},
 		{5, 6, nil},
 	}
 
-	err := b.load(puzzleFile)
+	err := b.Load(puzzleFile)
 	if err != nil {
 		t.Errorf("error loading puzzle file: %s", err)
 	}
 
 	for _, test := range tests {
 		got := b.checkRow(test.num, test.row)
-		if got != test.want {
-			t.Errorf("CheckRow(%d, %d) = %#+v; want %#+v", test.num, test.row, got, test.want)
+		if !sameConflict(got, test.want) {
+			t.Errorf("checkRow(%d, %d) = %v; want %v", test.num, test.row, got, test.want)
 		}
 	}
 }
 
 func TestCheckCol(t *testing.T) {
-	b := board()
+	b := NewBoard()
 	// numbers to check against the current state of the board
 	var tests = []struct {
 		num  int
 		col  int
-		want interface{}
+		want *ConflictError
 	}{
-		{1, 0, "number 1 found in cell [70]"},
-		{5, 0, "number 5 found in cell [00]"},
+		{1, 0, conflict(ColumnUnit, 1, 7, 0)},
+		{5, 0, conflict(ColumnUnit, 5, 0, 0)},
 		{4, 0, nil},
-		{4, 8, "number 4 found in cell [28]"},
-		{3, 6, "number 3 found in cell [46]"},
+		{4, 8, conflict(ColumnUnit, 4, 2, 8)},
+		{3, 6, conflict(ColumnUnit, 3, 4, 6)},
 		{5, 6, nil},
 	}
 
-	err := b.load(puzzleFile)
+	err := b.Load(puzzleFile)
 	if err != nil {
 		t.Errorf("error loading puzzle file: %s", err)
 	}
 
 	for _, test := range tests {
 		got := b.checkCol(test.num, test.col)
-		if got != test.want {
+		if !sameConflict(got, test.want) {
 			t.Errorf("checkCol(%d, %d) = %v; want %v", test.num, test.col, got, test.want)
 		}
 	}
 }
 
 func TestCheckBox(t *testing.T) {
-	b := board()
+	b := NewBoard()
 	// numbers to check against the current state of the board
 	var tests = []struct {
 		num  int
 		row  int
 		col  int
-		want interface{}
+		want *ConflictError
 	}{
-		{1, 0, 1, "number 1 found in cell [02]"},
-		{5, 0, 2, "number 5 found in cell [00]"},
+		{1, 0, 1, conflict(BoxUnit, 1, 0, 2)},
+		{5, 0, 2, conflict(BoxUnit, 5, 0, 0)},
 		{4, 3, 0, nil},
-		{4, 3, 8, "number 4 found in cell [57]"},
-		{3, 3, 6, "number 3 found in cell [46]"},
+		{4, 3, 8, conflict(BoxUnit, 4, 5, 7)},
+		{3, 3, 6, conflict(BoxUnit, 3, 4, 6)},
 		{5, 3, 6, nil},
 	}
 
-	err := b.load(puzzleFile)
+	err := b.Load(puzzleFile)
 	if err != nil {
 		t.Errorf("error loading puzzle file: %s", err)
 	}
 
 	for _, test := range tests {
 		got := b.checkBox(test.num, test.row, test.col)
-		if got != test.want {
+		if !sameConflict(got, test.want) {
 			t.Errorf("checkBox(%d, %d, %d) = %v; want %v", test.num, test.row, test.col, got, test.want)
 		}
 	}
 }
 
-func TestMapValues(t *testing.T) {
-	b := board()
-	var tests = []struct {
-		row int
-		col int
-		set int
-	}{
-		{5, 3, 7},
-		{0, 8, 5},
-		{4, 4, 3},
-		{2, 5, 2},
-		{1, 0, 6},
-		{5, 3, 8},
-		{5, 1, 1},
-		{8, 1, 4},
-		{6, 3, 2},
+func TestCheckNum(t *testing.T) {
+	b := NewBoard()
+	if err := b.Load(puzzleFile); err != nil {
+		t.Fatalf("error loading puzzle file: %s", err)
 	}
-	for _, test := range tests {
-		t.Logf("=== start test %#v", test)
-		// set cell's number
-		b[test.row][test.col].Number = test.set
-		// make new values map of board
-		vmap := b.mapValues()
-		t.Logf("values map for %d: %#+v\n", test.set, vmap[test.set])
-		// expect to find the cell set on the vmap
-		for i := 0; i < len(vmap[test.set]); i++ {
-			if vmap[test.set][i].Number == test.set &&
-				vmap[test.set][i].row == test.row &&
-				vmap[test.set][i].col == test.col {
-				t.Logf("number %d for [%d%d] found in %#v\n", test.set, test.row, test.col, vmap[test.set][i])
-				break
-			}
-			// reached the end of the vmap for set number
-			if i == len(vmap[test.set])-1 {
-				t.Errorf("%d not found for cell [%d%d]", test.set, test.row, test.col)
-			}
+
+	// the row is checked first, then the column and the box
+	err := b.checkNum(5, 1, 1)
+	var ce *ConflictError
+	if !errors.As(err, &ce) {
+		t.Fatalf("checkNum(5, 1, 1) = %v; want a conflict", err)
+	}
+	want := conflict(BoxUnit, 5, 0, 0)
+	want.At = Cell{row: 1, col: 1}
+	if !sameConflict(ce, want) {
+		t.Errorf("checkNum(5, 1, 1) = %#v; want %#v", ce, want)
+	}
+	if got := err.Error(); got != "number 5 found in cell [00] in the same box" {
+		t.Errorf("error: %q", got)
+	}
+
+	if err := b.checkNum(4, 1, 1); err != nil {
+		t.Errorf("checkNum(4, 1, 1) = %v; want nil", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	b := NewBoard()
+	if err := b.Load(puzzleFile); err != nil {
+		t.Fatalf("error loading puzzle file: %s", err)
+	}
+	if conflicts := b.Validate(); len(conflicts) != 0 {
+		t.Errorf("puzzle conflicts: %v", conflicts)
+	}
+
+	// 5 in [10] clashes with [00] in its column and box
+	b[1][0].Number = 5
+	col := conflict(ColumnUnit, 5, 0, 0)
+	col.At = Cell{row: 1, col: 0}
+	box := conflict(BoxUnit, 5, 0, 0)
+	box.At = Cell{row: 1, col: 0}
+	want := []*ConflictError{col, box}
+
+	got := b.Validate()
+	if len(got) != len(want) {
+		t.Fatalf("conflicts: %v; want %v", got, want)
+	}
+	for i := range want {
+		if !sameConflict(got[i], want[i]) {
+			t.Errorf("conflict %d: %#v; want %#v", i, got[i], want[i])
 		}
-		t.Logf("--- end test %#v", test)
 	}
 }
 
 func TestSaveState(t *testing.T) {
-	b := board()
-	err := b.load(puzzleFile)
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+
+	b := NewBoard()
+	err := b.Load(puzzleFile)
 	if err != nil {
 		t.Errorf("error loading state file: %s", err)
 	}
 
-	b[0][0].Number = 15
+	b[1][0].Number = 6
 
 	// save puzzle state
-	if err := b.save(); err != nil {
+	if err := b.Save(stateFile); err != nil {
 		t.Logf("error saving puzzle: %s", err)
 	}
 
-	err = b.load(stateFile)
+	err = b.Load(stateFile)
 	if err != nil {
 		t.Errorf("error loading state file: %s", err)
 	}
 
-	if b[0][0].Number != 15 {
+	if b[1][0].Number != 6 {
 		t.Error("the number was not saved")
 	}
 }
+
+func TestGivens(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+
+	b := NewBoard()
+	err := b.Load(puzzleFile)
+	if err != nil {
+		t.Fatalf("error loading puzzle file: %s", err)
+	}
+	b.MarkGivens()
+
+	if err := b.Move(Move{0, 0, PlaceMove, 4}); err == nil || b[0][0].Number != 5 {
+		t.Errorf("given [00] changed to %d", b[0][0].Number)
+	}
+	if err := b.Move(Move{1, 0, PlaceMove, 6}); err != nil {
+		t.Errorf("move r1c0=6: %s", err)
+	}
+
+	// givens survive saving and loading
+	if err := b.Save(stateFile); err != nil {
+		t.Fatalf("error saving puzzle: %s", err)
+	}
+	r := NewBoard()
+	if err := r.Load(stateFile); err != nil {
+		t.Fatalf("error loading state file: %s", err)
+	}
+	if !r[0][0].Given || r[1][0].Given || r[1][0].Number != 6 {
+		t.Errorf("[00] given: %v, [10] given: %v = %d", r[0][0].Given, r[1][0].Given, r[1][0].Number)
+	}
+
+	r.Reset()
+	if r[1][0].Number != 0 || r[0][0].Number != 5 {
+		t.Errorf("after reset [00] = %d, [10] = %d", r[0][0].Number, r[1][0].Number)
+	}
+}
@@ -0,0 +1,75 @@
+package dokusu
+
+import "math/bits"
+
+var fishes = map[int]Technique{2: XWing, 3: Swordfish, 4: Jellyfish}
+
+// fish (x-wing, swordfish, jellyfish): a number's marks in n rows
+// all lie in the same n columns, so the number is removed from the
+// rest of those columns; same with rows and columns swapped
+func findFish(b *Board, size int) (Step, bool) {
+	for n := 1; n < 10; n++ {
+		for _, byRow := range []bool{true, false} {
+			base, cover := units[:9], units[9:18]
+			if !byRow {
+				base, cover = cover, base
+			}
+
+			var lines []Unit
+			var where []uint16 // bit i set if number fits in cover line i
+			var cells [][]Cell
+			for _, u := range base {
+				pos := b.positions(u, n)
+				if len(pos) < 2 || len(pos) > size {
+					continue
+				}
+				var mask uint16
+				for _, c := range pos {
+					if byRow {
+						mask |= 1 << c.col
+					} else {
+						mask |= 1 << c.row
+					}
+				}
+				lines = append(lines, u)
+				where = append(where, mask)
+				cells = append(cells, pos)
+			}
+
+			for _, combo := range combinations(len(lines), size) {
+				var mask uint16
+				for _, j := range combo {
+					mask |= where[j]
+				}
+				if bits.OnesCount16(mask) != size {
+					continue
+				}
+
+				var elim, except, fish []Cell
+				var baseUnits, coverUnits []Unit
+				for _, j := range combo {
+					baseUnits = append(baseUnits, lines[j])
+					except = append(except, lines[j].cells[:]...)
+					fish = append(fish, cells[j]...)
+				}
+				for i := 0; i < 9; i++ {
+					if mask&(1<<i) == 0 {
+						continue
+					}
+					coverUnits = append(coverUnits, cover[i])
+					elim = append(elim, b.eliminate(cover[i].cells[:], 1<<n, except)...)
+				}
+				if len(elim) > 0 {
+					return Step{
+						Technique:  fishes[size],
+						Units:      append(baseUnits, coverUnits...),
+						Cells:      fish,
+						Digits:     []int{n},
+						Eliminated: elim,
+					}, true
+				}
+			}
+		}
+	}
+	return Step{}, false
+}
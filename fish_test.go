@@ -0,0 +1,66 @@
+package dokusu
+
+import (
+	"testing"
+)
+
+func TestFish(t *testing.T) {
+	var tests = []struct {
+		size int
+		rows map[int][]int // columns left for number 5 in each row
+		want Technique
+	}{
+		{2, map[int][]int{1: {2, 7}, 4: {2, 7}}, XWing},
+		{3, map[int][]int{0: {1, 4}, 3: {4, 7}, 6: {1, 7}}, Swordfish},
+		{4, map[int][]int{0: {0, 3}, 2: {3, 5}, 4: {5, 8}, 6: {0, 8}}, Jellyfish},
+	}
+
+	for _, test := range tests {
+		b := markedBoard(nil)
+		cols := make(map[int]bool)
+		for row, cs := range test.rows {
+			var except [][2]int
+			for _, col := range cs {
+				except = append(except, [2]int{row, col})
+				cols[col] = true
+			}
+			unmark(&b, rowUnit(row), 5, except...)
+		}
+
+		s, ok := findFish(&b, test.size)
+		if !ok || s.Technique != test.want {
+			t.Errorf("got %s; want %s", s, test.want)
+			continue
+		}
+		// 5 is removed from the fish columns, outside the fish rows
+		want := make(map[[2]int][]int)
+		for row := 0; row < 9; row++ {
+			if _, ok := test.rows[row]; ok {
+				continue
+			}
+			for col := range cols {
+				want[[2]int{row, col}] = []int{5}
+			}
+		}
+		checkEliminated(t, s, want)
+	}
+}
+
+func TestFishByColumn(t *testing.T) {
+	b := markedBoard(nil)
+	unmark(&b, colUnit(3), 8, [2]int{0, 3}, [2]int{5, 3})
+	unmark(&b, colUnit(6), 8, [2]int{0, 6}, [2]int{5, 6})
+
+	s, ok := findFish(&b, 2)
+	if !ok || s.Technique != XWing {
+		t.Fatalf("got %s; want x-wing", s)
+	}
+	want := make(map[[2]int][]int)
+	for col := 0; col < 9; col++ {
+		if col != 3 && col != 6 {
+			want[[2]int{0, col}] = []int{8}
+			want[[2]int{5, col}] = []int{8}
+		}
+	}
+	checkEliminated(t, s, want)
+}
@@ -0,0 +1,118 @@
+package dokusu
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Format is how a board is written
+type Format int
+
+const (
+	JSONFormat Format = iota // the board as in puzzle files
+	LineFormat               // 81 characters, . for empty cells
+	GridFormat               // nine lines with box borders
+)
+
+var formatNames = [...]string{"json", "line", "grid"}
+
+func (f Format) String() string {
+	if f < JSONFormat || f > GridFormat {
+		return fmt.Sprintf("format %d", int(f))
+	}
+	return formatNames[f]
+}
+
+// ParseFormat parses a format's name
+func ParseFormat(s string) (Format, error) {
+	for f, name := range formatNames {
+		if s == name {
+			return Format(f), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown format %q, use one of %s", s, strings.Join(formatNames[:], ", "))
+}
+
+// ParseText reads a board from text, row by row: digits, with 0
+// or . for empty cells; other characters, like the spaces and
+// borders of the grid format, are skipped. Numbers are givens.
+func ParseText(s string) (Board, error) {
+	b := NewBoard()
+	n := 0
+	for _, r := range s {
+		if r != '.' && (r < '0' || r > '9') {
+			continue
+		}
+		if n < 81 && r != '.' {
+			b[n/9][n%9].Number = int(r - '0')
+		}
+		n++
+	}
+	if n != 81 {
+		return Board{}, fmt.Errorf("puzzle has %d cells, want 81", n)
+	}
+	b.MarkGivens()
+
+	return b, nil
+}
+
+// Line writes the board in 81 characters, . for empty cells
+func (b *Board) Line() string {
+	var sb strings.Builder
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			sb.WriteByte(digit(b[row][col].Number))
+		}
+	}
+	return sb.String()
+}
+
+// Grid writes the board in nine lines, e.g.
+//
+//	5 3 1 | . . 9 | 6 2 .
+//
+// with a line of dashes between boxes
+func (b *Board) Grid() string {
+	var sb strings.Builder
+	for row := 0; row < 9; row++ {
+		if row == 3 || row == 6 {
+			sb.WriteString("------+-------+------\n")
+		}
+		for col := 0; col < 9; col++ {
+			switch {
+			case col == 3 || col == 6:
+				sb.WriteString(" | ")
+			case col > 0:
+				sb.WriteByte(' ')
+			}
+			sb.WriteByte(digit(b[row][col].Number))
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// digit of a cell's number, . if empty
+func digit(n int) byte {
+	if n < 1 || n > 9 {
+		return '.'
+	}
+	return byte('0' + n)
+}
+
+// Format writes the board in a format, ending with a new line
+func (b *Board) Format(f Format) ([]byte, error) {
+	switch f {
+	case LineFormat:
+		return []byte(b.Line() + "\n"), nil
+	case GridFormat:
+		return []byte(b.Grid()), nil
+	}
+
+	j, err := json.MarshalIndent(b, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	return append(j, '\n'), nil
+}
@@ -0,0 +1,43 @@
+package dokusu
+
+import "testing"
+
+func TestParseText(t *testing.T) {
+	b := NewBoard()
+	if err := b.Load(puzzleFile); err != nil {
+		t.Fatalf("error loading puzzle: %s", err)
+	}
+
+	for _, f := range []Format{LineFormat, GridFormat} {
+		text, err := b.Format(f)
+		if err != nil {
+			t.Fatalf("%s: %s", f, err)
+		}
+		p, err := ParseText(string(text))
+		if err != nil {
+			t.Fatalf("%s: %s", f, err)
+		}
+		if !sameNumbers(p, b) {
+			t.Errorf("%s: board changed when read back:\n%s", f, text)
+		}
+		if !p[0][0].Given || p[0][3].Given {
+			t.Errorf("%s: numbers read are not givens", f)
+		}
+	}
+
+	if _, err := ParseText("123"); err == nil {
+		t.Error("short puzzle read")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, f := range []Format{JSONFormat, LineFormat, GridFormat} {
+		got, err := ParseFormat(f.String())
+		if err != nil || got != f {
+			t.Errorf("format %s parsed as %s, %v", f, got, err)
+		}
+	}
+	if _, err := ParseFormat("csv"); err == nil {
+		t.Error("unknown format parsed")
+	}
+}
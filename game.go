@@ -0,0 +1,189 @@
+package dokusu
+
+import (
+	"fmt"
+	"time"
+)
+
+// Game is a puzzle being played: the board, the moves made
+// on it, which can be undone and redone, and the time played
+type Game struct {
+	Board   Board
+	base    [9][9]savedCell // the givens, before any move
+	elapsed time.Duration   // played before this session
+	started time.Time       // when this session started
+	history []Move          // moves made, oldest first
+	pos     int             // moves from pos on were undone
+}
+
+// NewGame starts a game on a puzzle; the game starts from its
+// givens, and numbers and marks of the player's already on the
+// board are taken as the first moves, so they can be undone
+func NewGame(b Board) *Game {
+	g := &Game{Board: b}
+	start := b
+	start.Reset()
+	g.base = start.cells()
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			c := b[row][col]
+			switch {
+			case c.Given:
+			case c.Number > 0:
+				g.history = append(g.history, Move{Row: row, Col: col, Number: c.Number})
+			default:
+				for _, n := range c.Marks {
+					g.history = append(g.history, Move{Row: row, Col: col, Kind: MarkMove, Number: n})
+				}
+			}
+		}
+	}
+	g.pos = len(g.history)
+	return g
+}
+
+// LoadGame resumes a game saved in a file; the board is
+// checked like Load does
+func LoadGame(f string) (*Game, error) {
+	s, err := readSave(f)
+	if err != nil {
+		return nil, err
+	}
+
+	b := NewBoard()
+	b.setCells(s.Cells)
+	if err := b.Check(false); err != nil {
+		return nil, fmt.Errorf("%s: %w", f, err)
+	}
+	if s.Base == nil {
+		// a puzzle, or a board saved without a game
+		return NewGame(b), nil
+	}
+
+	g := &Game{
+		Board:   b,
+		elapsed: time.Duration(s.Elapsed * float64(time.Second)),
+		history: s.History,
+		pos:     len(s.History) - s.Undone,
+	}
+
+	var base Board
+	base.setCells(*s.Base)
+	g.base = base.cells()
+
+	return g, nil
+}
+
+// Start the clock of a session of play
+func (g *Game) Start() {
+	g.started = time.Now()
+}
+
+// PlayTime is the time played so far, to the second
+func (g *Game) PlayTime() time.Duration {
+	d := g.elapsed
+	if !g.started.IsZero() {
+		d += time.Since(g.started)
+	}
+	return d.Round(time.Second)
+}
+
+// Moves made and not undone
+func (g *Game) Moves() []Move {
+	return append([]Move(nil), g.history[:g.pos]...)
+}
+
+// Save the game to a file
+func (g *Game) Save(f string) error {
+	base := g.base
+	return g.Board.writeSave(f, saveFile{
+		Elapsed: g.PlayTime().Seconds(),
+		History: g.history,
+		Undone:  len(g.history) - g.pos,
+		Base:    &base,
+	})
+}
+
+// Move makes a move on the board and records it, dropping
+// the moves undone before it; moves that change nothing,
+// like those on givens, are not recorded
+func (g *Game) Move(m Move) error {
+	err := g.Board.Move(m)
+	if err == nil || (m.Kind == PlaceMove && !g.Board[m.Row][m.Col].Given) {
+		g.history = append(g.history[:g.pos], m)
+		g.pos++
+	}
+	return err
+}
+
+// Undo the last move; false if there is none
+func (g *Game) Undo() bool {
+	if g.pos == 0 {
+		return false
+	}
+	g.GoTo(g.pos - 1)
+	return true
+}
+
+// Redo the last move undone; false if there is none
+func (g *Game) Redo() bool {
+	if g.pos == len(g.history) {
+		return false
+	}
+	g.GoTo(g.pos + 1)
+	return true
+}
+
+// GoTo sets the board as it was after the first n moves,
+// replaying them on the givens
+func (g *Game) GoTo(n int) {
+	g.Board.setCells(g.base)
+	for _, m := range g.history[:n] {
+		g.Board.Move(m)
+	}
+	g.Board.FlagInvalid()
+	g.pos = n
+}
+
+// BackToValid undoes moves back to the last point where the
+// board had no errors, i.e. no clashing numbers and none that
+// differ from the solution; returns the number of moves undone
+func (g *Game) BackToValid() int {
+	b := NewBoard()
+	b.setCells(g.base)
+
+	// without a solution only clashes are errors
+	var sol *Board
+	s := b.givens()
+	if found, err := s.Solve(); err == nil {
+		sol = &found
+	}
+
+	last := 0
+	for i, m := range g.history[:g.pos] {
+		b.Move(m)
+		if !b.hasErrors(sol) {
+			last = i + 1
+		}
+	}
+	undone := g.pos - last
+	g.GoTo(last)
+	return undone
+}
+
+// true if numbers clash or differ from the solution, if any
+func (b *Board) hasErrors(sol *Board) bool {
+	b.FlagInvalid()
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			c := b[row][col]
+			if c.Invalid {
+				return true
+			}
+			if sol != nil && c.Number > 0 && c.Number != sol[row][col].Number {
+				return true
+			}
+		}
+	}
+	return false
+}
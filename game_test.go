@@ -0,0 +1,151 @@
+package dokusu
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// a new game on the puzzle file, its solution and its first empty cells
+func testGame(t *testing.T) (*Game, Board, []Cell) {
+	b := NewBoard()
+	if err := b.Load(puzzleFile); err != nil {
+		t.Fatalf("error loading puzzle: %s", err)
+	}
+	b.MarkGivens()
+	sol, err := b.Solve()
+	if err != nil {
+		t.Fatalf("puzzle cannot be solved: %s", err)
+	}
+
+	var empty []Cell
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			if b[row][col].Number == 0 {
+				empty = append(empty, Cell{row: row, col: col})
+			}
+		}
+	}
+	return NewGame(b), sol, empty
+}
+
+func TestUndoRedo(t *testing.T) {
+	g, sol, empty := testGame(t)
+	e0, e1 := empty[0], empty[1]
+
+	g.Move(Move{Row: e0.row, Col: e0.col, Kind: MarkMove, Number: 3})
+	g.Move(Move{Row: e0.row, Col: e0.col, Number: sol[e0.row][e0.col].Number})
+	g.Move(Move{Row: e0.row, Col: e0.col, Number: 0}) // erase
+	if g.Board[e0.row][e0.col].Number != 0 {
+		t.Fatal("number was not erased")
+	}
+
+	if !g.Undo() || g.Board[e0.row][e0.col].Number != sol[e0.row][e0.col].Number {
+		t.Error("undo did not bring the erased number back")
+	}
+	if !g.Undo() || g.Board[e0.row][e0.col].Number != 0 || !g.Board[e0.row][e0.col].HasMark(3) {
+		t.Error("undo did not bring the candidate mark back")
+	}
+	if !g.Undo() || g.Board[e0.row][e0.col].HasMark(3) {
+		t.Error("undo did not remove the candidate mark")
+	}
+	if g.Undo() {
+		t.Error("undo past the start of the game")
+	}
+
+	if !g.Redo() || !g.Board[e0.row][e0.col].HasMark(3) {
+		t.Error("redo did not add the candidate mark")
+	}
+
+	// a new move drops the moves undone
+	g.Move(Move{Row: e1.row, Col: e1.col, Number: sol[e1.row][e1.col].Number})
+	if g.Redo() {
+		t.Error("redo after a new move")
+	}
+	if len(g.history) != 2 {
+		t.Errorf("history has %d moves, want 2", len(g.history))
+	}
+}
+
+func TestBackToValid(t *testing.T) {
+	g, sol, empty := testGame(t)
+	e0, e1, e2 := empty[0], empty[1], empty[2]
+	wrong := sol[e1.row][e1.col].Number%9 + 1
+
+	g.Move(Move{Row: e0.row, Col: e0.col, Number: sol[e0.row][e0.col].Number})
+	g.Move(Move{Row: e1.row, Col: e1.col, Number: wrong})
+	g.Move(Move{Row: e2.row, Col: e2.col, Number: sol[e2.row][e2.col].Number})
+
+	if n := g.BackToValid(); n != 2 {
+		t.Errorf("undid %d moves, want 2", n)
+	}
+	if g.Board[e0.row][e0.col].Number == 0 || g.Board[e1.row][e1.col].Number != 0 {
+		t.Error("board is not back to the last point with no errors")
+	}
+	if !g.Redo() || g.Board[e1.row][e1.col].Number != wrong {
+		t.Error("moves undone cannot be redone")
+	}
+}
+
+func TestSaveUndone(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+
+	g, sol, empty := testGame(t)
+	for _, e := range empty[:3] {
+		g.Move(Move{Row: e.row, Col: e.col, Number: sol[e.row][e.col].Number})
+	}
+	g.Undo()
+	if err := g.Save(stateFile); err != nil {
+		t.Fatalf("error saving game: %s", err)
+	}
+
+	r, err := LoadGame(stateFile)
+	if err != nil {
+		t.Fatalf("error loading game: %s", err)
+	}
+	if r.pos != 2 || len(r.history) != 3 {
+		t.Errorf("loaded %d moves with %d done, want 3 with 2 done", len(r.history), r.pos)
+	}
+	e := empty[2]
+	if !r.Redo() || r.Board[e.row][e.col].Number != sol[e.row][e.col].Number {
+		t.Error("undone move cannot be redone after loading")
+	}
+	for r.Undo() {
+	}
+	for g.Undo() {
+	}
+	if !sameNumbers(r.Board, g.Board) {
+		t.Error("undoing every move does not bring the board back to the givens")
+	}
+}
+
+func TestSaveBase(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+
+	// a game started on a board with numbers of the player's,
+	// as when a saved game is repaired, takes them as moves
+	g, sol, empty := testGame(t)
+	e0, e1, e2 := empty[0], empty[1], empty[2]
+	g.Board[e0.row][e0.col].Number = sol[e0.row][e0.col].Number
+	g = NewGame(g.Board)
+	if len(g.Moves()) != 1 {
+		t.Fatalf("new game has moves %v; want the player's number", g.Moves())
+	}
+	g.Move(Move{Row: e1.row, Col: e1.col, Number: sol[e1.row][e1.col].Number})
+	g.Move(Move{Row: e2.row, Col: e2.col, Number: sol[e2.row][e2.col].Number})
+	if err := g.Save(stateFile); err != nil {
+		t.Fatalf("error saving game: %s", err)
+	}
+
+	r, err := LoadGame(stateFile)
+	if err != nil {
+		t.Fatalf("error loading game: %s", err)
+	}
+	r.GoTo(0)
+	g.GoTo(0)
+	if !sameNumbers(r.Board, g.Board) || r.Board[e0.row][e0.col].Number != 0 || !r.Board[0][0].Given {
+		t.Errorf("resetting after loading gives:\n%s", r.Board.Grid())
+	}
+	if !r.Redo() || r.Board[e0.row][e0.col].Number != sol[e0.row][e0.col].Number || r.Board[e0.row][e0.col].Given {
+		t.Error("the player's number cannot be redone after a reset")
+	}
+}
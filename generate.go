@@ -0,0 +1,264 @@
+package dokusu
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Symmetry of the clues of a generated puzzle
+type Symmetry int
+
+const (
+	NoSymmetry         Symmetry = iota
+	RotationalSymmetry          // the same when turned by 180 degrees
+	MirrorSymmetry              // the same left to right
+	DiagonalSymmetry            // the same across the main diagonal
+)
+
+var symmetryNames = [...]string{"none", "rotational", "mirror", "diagonal"}
+
+func (s Symmetry) String() string {
+	if s < NoSymmetry || s > DiagonalSymmetry {
+		return fmt.Sprintf("symmetry %d", int(s))
+	}
+	return symmetryNames[s]
+}
+
+// ParseSymmetry parses a symmetry's name
+func ParseSymmetry(s string) (Symmetry, error) {
+	for sym, name := range symmetryNames {
+		if s == name {
+			return Symmetry(sym), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown symmetry %q, use one of %s", s, strings.Join(symmetryNames[:], ", "))
+}
+
+// mirror returns the cell matching [row col] in the symmetry
+func (s Symmetry) mirror(row, col int) (int, int) {
+	switch s {
+	case RotationalSymmetry:
+		return 8 - row, 8 - col
+	case MirrorSymmetry:
+		return row, 8 - col
+	case DiagonalSymmetry:
+		return col, row
+	}
+	return row, col
+}
+
+// GenOptions are how a puzzle is generated
+type GenOptions struct {
+	Clues    int           // clues to stop at; 0 removes as many as possible
+	Symmetry Symmetry      // clues are removed in pairs that keep it
+	Timeout  time.Duration // stop removing clues after it; 0 for no limit
+	Seed     int64         // 0 picks one from the clock
+
+	// Minimal removes clues one at a time after the others, even
+	// if it breaks the symmetry or goes below Clues, until every
+	// clue left is needed; it is done even after the timeout
+	Minimal bool
+}
+
+// Puzzle is a generated puzzle and its seed; the same seed and
+// options generate it again, unless the timeout cut it short
+type Puzzle struct {
+	Board Board
+	Seed  int64
+}
+
+// newSeed picks a seed from the clock, never 0
+func newSeed() int64 {
+	if seed := time.Now().UnixNano(); seed != 0 {
+		return seed
+	}
+	return 1
+}
+
+// DailySeed is the seed of a day's puzzle, e.g. 20260314,
+// so that everyone generates the same puzzle on the same day;
+// days are those of UTC, whatever the time zone of the date
+func DailySeed(date time.Time) int64 {
+	y, m, d := date.UTC().Date()
+	return int64(y*10000 + int(m)*100 + d)
+}
+
+// Daily generates the puzzle of a day, see DailySeed
+func Daily(date time.Time) Puzzle {
+	return Generate(GenOptions{Seed: DailySeed(date)})
+}
+
+// RandomGrid returns a random solved grid for a seed
+func RandomGrid(seed int64) Board {
+	return randomGrid(rand.New(rand.NewSource(seed)))
+}
+
+// randomGrid fills the three boxes on the diagonal, which cannot
+// clash, at random, then solves the rest trying numbers in
+// random order
+func randomGrid(r *rand.Rand) Board {
+	for {
+		b := NewBoard()
+		b.gen3boxes(r)
+		s, err := newSolver(&b)
+		if err != nil {
+			continue
+		}
+		s.rand = r
+
+		found := false
+		s.search(func() bool {
+			for row := 0; row < 9; row++ {
+				for col := 0; col < 9; col++ {
+					b[row][col].Number = s.cells[row][col]
+				}
+			}
+			found = true
+			return false
+		})
+		if found {
+			return b
+		}
+		// cannot happen, but try other boxes
+	}
+}
+
+// Generate returns a puzzle with a single solution: the numbers
+// of a random grid are removed in random order, each one, along
+// with its mirror in the symmetry, as long as the solution stays
+// unique. The puzzle has more clues than asked for if no more
+// can be removed or time runs out.
+func Generate(opts GenOptions) Puzzle {
+	p, _ := generate(context.Background(), opts)
+	return p
+}
+
+// generate is Generate giving up with the context's error when
+// it is done before the puzzle is
+func generate(ctx context.Context, opts GenOptions) (Puzzle, error) {
+	var deadline time.Time
+	if opts.Timeout > 0 {
+		deadline = time.Now().Add(opts.Timeout)
+	}
+	if opts.Seed == 0 {
+		opts.Seed = newSeed()
+	}
+	r := rand.New(rand.NewSource(opts.Seed))
+
+	b := randomGrid(r)
+	clues := 81
+	var cells []int
+	for i := 0; i < 81; i++ {
+		cells = append(cells, i)
+	}
+	for _, i := range shuffle(r, cells) {
+		if err := ctx.Err(); err != nil {
+			return Puzzle{}, err
+		}
+		if clues <= opts.Clues || (!deadline.IsZero() && time.Now().After(deadline)) {
+			break
+		}
+		row, col := i/9, i%9
+		mrow, mcol := opts.Symmetry.mirror(row, col)
+		pair := []*Cell{&b[row][col]}
+		if mrow != row || mcol != col {
+			pair = append(pair, &b[mrow][mcol])
+		}
+		if pair[0].Number == 0 || clues-len(pair) < opts.Clues {
+			continue
+		}
+
+		var numbers []int
+		for _, c := range pair {
+			numbers = append(numbers, c.Number)
+			c.Number = 0
+		}
+		if b.CountSolutions(2) == 1 {
+			clues -= len(pair)
+			continue
+		}
+		for j, c := range pair {
+			c.Number = numbers[j]
+		}
+	}
+
+	if opts.Minimal {
+		// a clue needed stays needed when others are removed,
+		// so one pass is enough
+		for _, i := range cells {
+			c := &b[i/9][i%9]
+			n := c.Number
+			if n == 0 {
+				continue
+			}
+			c.Number = 0
+			if b.CountSolutions(2) != 1 {
+				c.Number = n
+			}
+		}
+	}
+
+	b.MarkGivens()
+	return Puzzle{Board: b, Seed: opts.Seed}, nil
+}
+
+// GenerateRated generates puzzles until one rates in the band,
+// for at most limit, 0 for no limit. If none does in time, the
+// one rated closest to the band is returned with an error. The
+// first try is on the seed in the options, the next on seeds
+// drawn from it; the seed of the puzzle found generates it
+// again, rated or not.
+func GenerateRated(opts GenOptions, band Band, limit time.Duration) (Puzzle, Rating, error) {
+	return generateRated(context.Background(), opts, band, limit)
+}
+
+// generateRated is GenerateRated giving up with the context's
+// error when it is done before a puzzle is found
+func generateRated(ctx context.Context, opts GenOptions, band Band, limit time.Duration) (Puzzle, Rating, error) {
+	start := time.Now()
+	if opts.Seed == 0 {
+		opts.Seed = newSeed()
+	}
+	seeds := rand.New(rand.NewSource(opts.Seed))
+
+	var best Puzzle
+	var bestRating Rating
+	tries := 0
+	for {
+		try := opts
+		if tries > 0 {
+			try.Seed = seeds.Int63()
+		}
+		p, err := generate(ctx, try)
+		if err != nil {
+			return best, bestRating, err
+		}
+		r := p.Board.Grade()
+		tries++
+		if band.Contains(r) {
+			return p, r, nil
+		}
+		if tries == 1 || band.distance(r) < band.distance(bestRating) {
+			best, bestRating = p, r
+		}
+		if limit > 0 && time.Since(start) >= limit {
+			return best, bestRating, fmt.Errorf("no puzzle rated %s in %d tries, closest is %s", band, tries, bestRating)
+		}
+	}
+}
+
+// Clues counts the numbers set on the board
+func (b *Board) Clues() int {
+	n := 0
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			if b[row][col].Number > 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,167 @@
+package dokusu
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRandomGrid(t *testing.T) {
+	b1 := RandomGrid(1)
+	b2 := RandomGrid(2)
+	t.Logf("\n%s", b1.Grid())
+	if !b1.IsComplete() || !b2.IsComplete() {
+		t.Fatalf("grids not complete:\n%s\n%s", b1.Grid(), b2.Grid())
+	}
+	if sameNumbers(b1, b2) {
+		t.Error("grids of two seeds are the same")
+	}
+	if b := RandomGrid(1); !sameNumbers(b, b1) {
+		t.Error("grids of the same seed differ")
+	}
+
+	// every box varies, not only those on the diagonal
+	for seed := int64(3); seed < 13; seed++ {
+		if RandomGrid(seed)[0][3].Number != b1[0][3].Number {
+			return
+		}
+	}
+	t.Error("cell [03] always the same")
+}
+
+func TestGenerate(t *testing.T) {
+	var tests = []GenOptions{
+		{},
+		{Clues: 30},
+		{Symmetry: RotationalSymmetry},
+		{Clues: 32, Symmetry: MirrorSymmetry},
+		{Symmetry: DiagonalSymmetry},
+	}
+
+	for _, opts := range tests {
+		b := Generate(opts).Board
+		t.Logf("%+v:\n%s", opts, b.Grid())
+		if n := b.CountSolutions(2); n != 1 {
+			t.Fatalf("%+v: puzzle has %d solutions, want 1", opts, n)
+		}
+		if err := b.Check(true); err != nil {
+			t.Errorf("%+v: puzzle invalid: %s", opts, err)
+		}
+		if b.Clues() < opts.Clues || (opts == GenOptions{Clues: 30} && b.Clues() != 30) {
+			t.Errorf("%+v: %d clues", opts, b.Clues())
+		}
+
+		for row := 0; row < 9; row++ {
+			for col := 0; col < 9; col++ {
+				c := b[row][col]
+				if c.Given != (c.Number > 0) {
+					t.Errorf("%+v: cell %s = %d, given: %v", opts, c, c.Number, c.Given)
+				}
+				mrow, mcol := opts.Symmetry.mirror(row, col)
+				if c.Given != b[mrow][mcol].Given {
+					t.Errorf("%+v: cell %s is not like [%d%d]", opts, c, mrow, mcol)
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateMinimal(t *testing.T) {
+	// with no symmetry nor target, no clue can be removed
+	b := Generate(GenOptions{}).Board
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			if b[row][col].Number == 0 {
+				continue
+			}
+			r := b
+			r[row][col].Number = 0
+			if r.CountSolutions(2) == 1 {
+				t.Errorf("given %s can be removed", b[row][col])
+			}
+		}
+	}
+}
+
+func TestGenerateMinimalSymmetry(t *testing.T) {
+	for _, opts := range []GenOptions{
+		{Symmetry: RotationalSymmetry, Minimal: true},
+		{Clues: 40, Minimal: true},
+		{Timeout: time.Nanosecond, Minimal: true},
+	} {
+		b := Generate(opts).Board
+		if redundant, err := b.Redundant(); err != nil || len(redundant) > 0 {
+			t.Errorf("%+v: redundant clues %v, %v", opts, redundant, err)
+		}
+	}
+}
+
+func TestGenerateTimeout(t *testing.T) {
+	// out of time before the first clue is removed
+	b := Generate(GenOptions{Timeout: time.Nanosecond}).Board
+	if b.Clues() != 81 {
+		t.Errorf("%d clues removed after the timeout", 81-b.Clues())
+	}
+}
+
+func TestSeed(t *testing.T) {
+	opts := GenOptions{Symmetry: RotationalSymmetry, Seed: 42}
+	p1, p2 := Generate(opts), Generate(opts)
+	if p1.Seed != 42 || !sameNumbers(p1.Board, p2.Board) {
+		t.Errorf("seed 42 generated different puzzles:\n%s\n%s", p1.Board.Grid(), p2.Board.Grid())
+	}
+
+	// the seed reported generates the puzzle again
+	p := Generate(GenOptions{})
+	if p.Seed == 0 {
+		t.Fatal("no seed reported")
+	}
+	if again := Generate(GenOptions{Seed: p.Seed}); !sameNumbers(again.Board, p.Board) {
+		t.Errorf("seed %d generated different puzzles", p.Seed)
+	}
+	p, _, err := GenerateRated(GenOptions{Seed: 7}, TierBand(Medium), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again := Generate(GenOptions{Seed: p.Seed}); !sameNumbers(again.Board, p.Board) {
+		t.Errorf("rated puzzle's seed %d generated a different one", p.Seed)
+	}
+	if again, _, _ := GenerateRated(GenOptions{Seed: 7}, TierBand(Medium), 0); again.Seed != p.Seed {
+		t.Errorf("seed 7 found puzzles of seeds %d and %d", p.Seed, again.Seed)
+	}
+	if again, _, _ := GenerateRated(GenOptions{Seed: p.Seed}, TierBand(Medium), 0); again.Seed != p.Seed {
+		t.Errorf("seed %d found the puzzle of seed %d", p.Seed, again.Seed)
+	}
+}
+
+func TestDaily(t *testing.T) {
+	day := time.Date(2026, time.March, 14, 23, 59, 0, 0, time.UTC)
+	if seed := DailySeed(day); seed != 20260314 {
+		t.Errorf("DailySeed(%s) = %d", day, seed)
+	}
+	morning := time.Date(2026, time.March, 14, 7, 0, 0, 0, time.UTC)
+	if !sameNumbers(Daily(day).Board, Daily(morning).Board) {
+		t.Error("puzzles of the same day differ")
+	}
+	if sameNumbers(Daily(day).Board, Daily(day.Add(time.Hour)).Board) {
+		t.Error("puzzles of two days are the same")
+	}
+
+	// the same moment is the same day in every time zone
+	tokyo := time.FixedZone("UTC+9", 9*60*60)
+	denver := time.FixedZone("UTC-7", -7*60*60)
+	if a, b := DailySeed(day.In(tokyo)), DailySeed(day.In(denver)); a != 20260314 || b != 20260314 {
+		t.Errorf("DailySeed of %s in two zones = %d, %d", day, a, b)
+	}
+}
+
+func TestParseSymmetry(t *testing.T) {
+	for _, s := range []Symmetry{NoSymmetry, RotationalSymmetry, MirrorSymmetry, DiagonalSymmetry} {
+		got, err := ParseSymmetry(s.String())
+		if err != nil || got != s {
+			t.Errorf("ParseSymmetry(%q) = %v, %v", s, got, err)
+		}
+	}
+	if _, err := ParseSymmetry("spiral"); err == nil {
+		t.Error("ParseSymmetry(spiral) did not fail")
+	}
+}
@@ -0,0 +1,216 @@
+package dokusu
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Tier is a named difficulty level
+type Tier int
+
+const (
+	Easy Tier = iota
+	Medium
+	Hard
+	Expert
+	Diabolical
+)
+
+var tierNames = [...]string{"easy", "medium", "hard", "expert", "diabolical"}
+
+func (t Tier) String() string {
+	if t < Easy || t > Diabolical {
+		return fmt.Sprintf("tier %d", int(t))
+	}
+	return tierNames[t]
+}
+
+// ratings of techniques, on a scale similar to Sudoku Explainer's
+var ratings = map[Technique]float64{
+	HiddenSingle:     1.5,
+	NakedSingle:      2.3,
+	PointingPair:     2.6,
+	BoxLineReduction: 2.8,
+	NakedPair:        3.0,
+	XWing:            3.2,
+	HiddenPair:       3.4,
+	NakedTriple:      3.6,
+	Swordfish:        3.8,
+	HiddenTriple:     4.0,
+	XYWing:           4.2,
+	XYZWing:          4.4,
+	WWing:            4.4,
+	NakedQuad:        5.0,
+	Jellyfish:        5.2,
+	HiddenQuad:       5.4,
+	SimpleColoring:   5.6,
+	XCycle:           6.6,
+	AIC:              7.0,
+	UnitForcingChain: 7.5,
+	CellForcingChain: 8.0,
+}
+
+// rating of puzzles the logical solver cannot finish
+const unratedScore = 10.0
+
+// highest score of each tier but the last
+var tierScores = [...]float64{
+	Easy:   2.3, // singles
+	Medium: 2.8, // intersections
+	Hard:   4.4, // subsets, basic fish and wings
+	Expert: 7.0, // quads, coloring and chains
+}
+
+// tier for a score
+func tierFor(score float64) Tier {
+	for t, max := range tierScores {
+		if score <= max {
+			return Tier(t)
+		}
+	}
+	return Diabolical
+}
+
+// Rating of a puzzle by the techniques needed to solve it
+type Rating struct {
+	Score      float64 // score of the hardest technique
+	Tier       Tier
+	Hardest    Technique
+	Steps      int
+	Techniques map[Technique]int // steps taken with each technique
+	Solved     bool              // false if the techniques were not enough
+}
+
+// e.g. hard (4.2, xy-wing, 54 steps)
+func (r Rating) String() string {
+	if !r.Solved {
+		return fmt.Sprintf("%s (%.1f, beyond known techniques after %d steps)", r.Tier, r.Score, r.Steps)
+	}
+	return fmt.Sprintf("%s (%.1f, %s, %d steps)", r.Tier, r.Score, r.Hardest, r.Steps)
+}
+
+// Used are the techniques used, in the order they are tried,
+// which is that of their ratings
+func (r Rating) Used() []Technique {
+	var used []Technique
+	for t := range r.Techniques {
+		used = append(used, t)
+	}
+	sort.Slice(used, func(i, j int) bool {
+		ri, rj := ratings[used[i]], ratings[used[j]]
+		return ri < rj || ri == rj && used[i] < used[j]
+	})
+	return used
+}
+
+// Grade rates the board by solving it logically;
+// the board itself is not changed
+func (b *Board) Grade() Rating {
+	steps, l := b.LogicSolve()
+	r := Rating{
+		Steps:      len(steps),
+		Techniques: make(map[Technique]int),
+		Solved:     l.IsComplete(),
+	}
+	for _, s := range steps {
+		r.Techniques[s.Technique]++
+		if ratings[s.Technique] > r.Score {
+			r.Score = ratings[s.Technique]
+			r.Hardest = s.Technique
+		}
+	}
+	if !r.Solved {
+		r.Score = unratedScore
+	}
+	r.Tier = tierFor(r.Score)
+
+	return r
+}
+
+// ParseTier parses a tier's name
+func ParseTier(s string) (Tier, error) {
+	for t, name := range tierNames {
+		if s == name {
+			return Tier(t), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown tier %q, use one of %s", s, strings.Join(tierNames[:], ", "))
+}
+
+// Band is a range of ratings: scores from Min to Max, both
+// included, of puzzles that need the techniques listed
+type Band struct {
+	Min   float64
+	Max   float64
+	Needs []Technique
+}
+
+// TierBand is the band of the ratings in a tier
+func TierBand(t Tier) Band {
+	band := Band{Max: unratedScore}
+	if t < Diabolical {
+		band.Max = tierScores[t]
+	}
+	if t > Easy {
+		band.Min = unratedScore
+		for _, score := range ratings {
+			if score > tierScores[t-1] && score < band.Min {
+				band.Min = score
+			}
+		}
+	}
+	return band
+}
+
+// TechniqueBand is the band of puzzles that need a technique
+// and none rated higher, e.g. hard ones needing an x-wing
+func TechniqueBand(t Technique) Band {
+	return Band{Max: ratings[t], Needs: []Technique{t}}
+}
+
+// Contains reports if a rating is in the band
+func (b Band) Contains(r Rating) bool {
+	if r.Score < b.Min || r.Score > b.Max {
+		return false
+	}
+	for _, t := range b.Needs {
+		if r.Techniques[t] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// e.g. 2.4-3.2 needing x-wing
+func (b Band) String() string {
+	s := fmt.Sprintf("%.1f-%.1f", b.Min, b.Max)
+	if len(b.Needs) > 0 {
+		var names []string
+		for _, t := range b.Needs {
+			names = append(names, t.String())
+		}
+		s += " needing " + strings.Join(names, ", ")
+	}
+	return s
+}
+
+// distance of a rating from the band: of its score, and for each
+// technique needed and not used more than any score can be, the
+// more the further the score is from the technique's rating
+func (b Band) distance(r Rating) float64 {
+	var d float64
+	switch {
+	case r.Score < b.Min:
+		d = b.Min - r.Score
+	case r.Score > b.Max:
+		d = r.Score - b.Max
+	}
+	for _, t := range b.Needs {
+		if r.Techniques[t] == 0 {
+			d += unratedScore + math.Abs(ratings[t]-r.Score)
+		}
+	}
+	return d
+}
@@ -0,0 +1,139 @@
+package dokusu
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestGrade(t *testing.T) {
+	var tests = []struct {
+		puzzle int // in testPuzzles
+		tier   Tier
+		solved bool
+	}{
+		{0, Easy, true},
+		{1, Easy, true},
+		{2, Diabolical, false},
+		{3, Medium, true},
+		{4, Diabolical, false},
+	}
+
+	for _, test := range tests {
+		b := parseBoard(t, testPuzzles[test.puzzle])
+		r := b.Grade()
+		t.Logf("puzzle #%d: %s", test.puzzle, r)
+		if r.Tier != test.tier || r.Solved != test.solved {
+			t.Errorf("puzzle #%d graded %s; want %s", test.puzzle, r, test.tier)
+		}
+		if b[0][0].Marks != nil {
+			t.Errorf("puzzle #%d changed while grading", test.puzzle)
+		}
+	}
+}
+
+func TestUsed(t *testing.T) {
+	r := Rating{Techniques: map[Technique]int{HiddenPair: 1, WWing: 1, XWing: 2, XYZWing: 1, HiddenSingle: 9}}
+	want := []Technique{HiddenSingle, XWing, HiddenPair, XYZWing, WWing}
+	if got := r.Used(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Used = %v; want %v", got, want)
+	}
+}
+
+func TestTierFor(t *testing.T) {
+	var tests = []struct {
+		t    Technique
+		want Tier
+	}{
+		{HiddenSingle, Easy},
+		{NakedSingle, Easy},
+		{PointingPair, Medium},
+		{BoxLineReduction, Medium},
+		{NakedPair, Hard},
+		{XWing, Hard},
+		{WWing, Hard},
+		{NakedQuad, Expert},
+		{AIC, Expert},
+		{CellForcingChain, Diabolical},
+	}
+
+	for _, test := range tests {
+		if got := tierFor(ratings[test.t]); got != test.want {
+			t.Errorf("tierFor(%s) = %s; want %s", test.t, got, test.want)
+		}
+	}
+	if got := tierFor(unratedScore); got != Diabolical {
+		t.Errorf("tierFor(%.1f) = %s; want diabolical", unratedScore, got)
+	}
+}
+
+func TestBands(t *testing.T) {
+	// every technique is in the band of its tier, and of its own
+	for tech, score := range ratings {
+		r := Rating{Score: score, Tier: tierFor(score), Techniques: map[Technique]int{tech: 1}}
+		for tier := Easy; tier <= Diabolical; tier++ {
+			if got := TierBand(tier).Contains(r); got != (tier == r.Tier) {
+				t.Errorf("band %s of %s contains %s: %v", TierBand(tier), tier, tech, got)
+			}
+		}
+		if !TechniqueBand(tech).Contains(r) {
+			t.Errorf("band %s does not contain %s", TechniqueBand(tech), tech)
+		}
+	}
+
+	// an x-wing is not enough if the puzzle needs a swordfish too
+	r := Rating{Score: ratings[Swordfish], Techniques: map[Technique]int{XWing: 1, Swordfish: 1}}
+	if TechniqueBand(XWing).Contains(r) {
+		t.Errorf("band %s contains a swordfish", TechniqueBand(XWing))
+	}
+	if got := TierBand(Medium).String(); got != "2.6-2.8" {
+		t.Errorf("medium band is %s", got)
+	}
+}
+
+func TestBandDistance(t *testing.T) {
+	band := TechniqueBand(Jellyfish)
+	easy := Rating{Score: ratings[HiddenSingle], Techniques: map[Technique]int{HiddenSingle: 40}}
+	quad := Rating{Score: ratings[NakedQuad], Techniques: map[Technique]int{HiddenSingle: 30, NakedQuad: 1}}
+	over := Rating{Score: ratings[SimpleColoring], Techniques: map[Technique]int{Jellyfish: 1, SimpleColoring: 1}}
+	if d := band.distance(easy); d <= band.distance(quad) {
+		t.Errorf("easy puzzle at %.1f from %s, no further than one needing a naked quad", d, band)
+	}
+	if d := band.distance(quad); d <= band.distance(over) {
+		t.Errorf("puzzle without a jellyfish at %.1f from %s, no further than one with it", d, band)
+	}
+}
+
+func TestGenerateRated(t *testing.T) {
+	p, r, err := GenerateRated(GenOptions{}, TierBand(Easy), 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := p.Board
+	t.Logf("%s\n%s", r, b.Grid())
+	if r.Tier != Easy || b.Grade().Tier != Easy {
+		t.Errorf("puzzle rated %s; want easy", r)
+	}
+
+	// no puzzle rates that high; the closest is still a puzzle
+	p, r, err = GenerateRated(GenOptions{}, Band{Min: 20, Max: 30}, 50*time.Millisecond)
+	b = p.Board
+	if err == nil {
+		t.Errorf("puzzle rated %s in band 20-30", r)
+	}
+	if b.CountSolutions(2) != 1 {
+		t.Errorf("closest puzzle not unique:\n%s", b.Grid())
+	}
+
+	// the closest puzzle is rated nearer the technique needed
+	// than the easy puzzles most tries give
+	band := TechniqueBand(Jellyfish)
+	p, r, err = GenerateRated(GenOptions{Seed: 3}, band, time.Second)
+	if err == nil {
+		t.Skipf("puzzle needing a jellyfish found: %s", r)
+	}
+	b = p.Board
+	if got := b.Grade(); got.String() != r.String() || r.Tier == Easy {
+		t.Errorf("closest puzzle to %s rated %s (%s)", band, r, got)
+	}
+}
@@ -0,0 +1,65 @@
+package dokusu
+
+import (
+	"fmt"
+	"strings"
+)
+
+// hint stages, each one revealing more of the next step
+const (
+	HintRegion = iota + 1
+	HintTechnique
+	HintAnswer
+)
+
+// Hint finds the next logical step for the board's numbers;
+// the board is not changed
+func (b *Board) Hint() (Step, bool) {
+	l := *b
+	l.MarkCells()
+	return l.NextStep()
+}
+
+// ShowHint highlights a step on the board up to a hint stage;
+// first the region to look at, then the technique to use and
+// finally the exact placement or elimination. Returns the message
+// to show along with the board.
+func (b *Board) ShowHint(s Step, stage int) string {
+	switch stage {
+	case HintRegion:
+		return "look at " + b.selectRegion(s)
+
+	case HintTechnique:
+		region := b.selectRegion(s)
+		return fmt.Sprintf("try a %s in %s", s.Technique, region)
+
+	default:
+		b.Highlight(s)
+		return s.String()
+	}
+}
+
+// select the units a step was found in, or its cells if none;
+// returns the region's description
+func (b *Board) selectRegion(s Step) string {
+	if len(s.Units) == 0 {
+		for _, c := range s.Cells {
+			b[c.row][c.col].Selected = true
+		}
+		return "the highlighted cells"
+	}
+
+	var names []string
+	for _, u := range s.Units {
+		switch u.Kind {
+		case RowUnit:
+			b.selectRow(u.Index)
+		case ColumnUnit:
+			b.selectColumn(u.Index)
+		default:
+			b.selectBox(u.cells[0].row, u.cells[0].col)
+		}
+		names = append(names, u.String())
+	}
+	return strings.Join(names, ", ")
+}
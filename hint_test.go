@@ -0,0 +1,66 @@
+package dokusu
+
+import (
+	"testing"
+)
+
+func TestHint(t *testing.T) {
+	b := NewBoard()
+	err := b.Load(puzzleFile)
+	if err != nil {
+		t.Fatalf("error loading puzzle file: %s", err)
+	}
+
+	s, ok := b.Hint()
+	if !ok {
+		t.Fatal("no hint for puzzle")
+	}
+	if b[0][3].Marks != nil {
+		t.Error("board marked while looking for a hint")
+	}
+
+	// region first; the placed cell is in it, but not revealed
+	msg := b.ShowHint(s, HintRegion)
+	t.Logf("hint #1: %s", msg)
+	t.Logf("\n%s", b.Grid())
+	c := s.Placed[0]
+	if !b[c.row][c.col].Selected {
+		t.Errorf("cell %s not in the region selected", c)
+	}
+	if b[c.row][c.col].Blink {
+		t.Errorf("answer %s revealed early", c)
+	}
+	b.Clear()
+
+	msg = b.ShowHint(s, HintTechnique)
+	t.Logf("hint #2: %s", msg)
+	if msg != "try a "+s.Technique.String()+" in "+s.Units[0].String() {
+		t.Errorf("got hint %q; want technique and region", msg)
+	}
+	b.Clear()
+
+	msg = b.ShowHint(s, HintAnswer)
+	t.Logf("hint #3: %s", msg)
+	t.Logf("\n%s", b.Grid())
+	if !b[c.row][c.col].Blink {
+		t.Errorf("answer %s not shown", c)
+	}
+	if b[c.row][c.col].Number != 0 {
+		t.Errorf("hint placed %d in %s", b[c.row][c.col].Number, c)
+	}
+}
+
+func TestHintCells(t *testing.T) {
+	b := NewBoard()
+	s := Step{Technique: XYWing, Cells: []Cell{{row: 4, col: 4}, {row: 4, col: 0}, {row: 1, col: 4}}}
+
+	msg := b.ShowHint(s, HintRegion)
+	if msg != "look at the highlighted cells" {
+		t.Errorf("got hint %q", msg)
+	}
+	for _, c := range s.Cells {
+		if !b[c.row][c.col].Selected {
+			t.Errorf("cell %s not selected", c)
+		}
+	}
+}
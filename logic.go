@@ -0,0 +1,597 @@
+package dokusu
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// Technique is a human solving technique used by the logical solver
+type Technique int
+
+// techniques, mostly from simplest to hardest
+const (
+	HiddenSingle Technique = iota // i.e cross-hatching
+	NakedSingle
+	PointingPair
+	BoxLineReduction
+	NakedPair
+	HiddenPair
+	NakedTriple
+	HiddenTriple
+	NakedQuad
+	HiddenQuad
+	XWing
+	Swordfish
+	Jellyfish
+	XYWing
+	XYZWing
+	WWing
+	SimpleColoring
+	XCycle
+	AIC
+	CellForcingChain
+	UnitForcingChain
+)
+
+var techniqueNames = map[Technique]string{
+	HiddenSingle:     "hidden single",
+	NakedSingle:      "naked single",
+	PointingPair:     "pointing pair",
+	BoxLineReduction: "box/line reduction",
+	NakedPair:        "naked pair",
+	HiddenPair:       "hidden pair",
+	NakedTriple:      "naked triple",
+	HiddenTriple:     "hidden triple",
+	NakedQuad:        "naked quad",
+	HiddenQuad:       "hidden quad",
+	XWing:            "x-wing",
+	Swordfish:        "swordfish",
+	Jellyfish:        "jellyfish",
+	XYWing:           "xy-wing",
+	XYZWing:          "xyz-wing",
+	WWing:            "w-wing",
+	SimpleColoring:   "simple coloring",
+	XCycle:           "x-cycle",
+	AIC:              "alternating inference chain",
+	CellForcingChain: "cell forcing chain",
+	UnitForcingChain: "unit forcing chain",
+}
+
+func (t Technique) String() string {
+	if name, ok := techniqueNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("technique %d", int(t))
+}
+
+// ParseTechnique parses a technique's name, e.g. x-wing
+func ParseTechnique(s string) (Technique, error) {
+	for t, name := range techniqueNames {
+		if s == name {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown technique %q", s)
+}
+
+// UnitKind is either a row, a column or a 3x3 box
+type UnitKind int
+
+const (
+	RowUnit UnitKind = iota
+	ColumnUnit
+	BoxUnit
+)
+
+func (k UnitKind) String() string {
+	switch k {
+	case RowUnit:
+		return "row"
+	case ColumnUnit:
+		return "column"
+	default:
+		return "box"
+	}
+}
+
+// Unit is one of the board's 27 rows, columns and boxes;
+// boxes are numbered 0 to 8 from left to right, top to bottom
+type Unit struct {
+	Kind  UnitKind
+	Index int
+	cells [9]Cell // only row and col are set
+}
+
+func (u Unit) String() string {
+	return fmt.Sprintf("%s %d", u.Kind, u.Index)
+}
+
+// units of the board, rows first then columns and boxes
+var units = makeUnits()
+
+func makeUnits() [27]Unit {
+	var us [27]Unit
+	for i := 0; i < 9; i++ {
+		us[i] = Unit{Kind: RowUnit, Index: i}
+		us[9+i] = Unit{Kind: ColumnUnit, Index: i}
+		us[18+i] = Unit{Kind: BoxUnit, Index: i}
+		brow, bcol := i/3*3, i%3*3
+		for j := 0; j < 9; j++ {
+			us[i].cells[j] = Cell{row: i, col: j}
+			us[9+i].cells[j] = Cell{row: j, col: i}
+			us[18+i].cells[j] = Cell{row: brow + j/3, col: bcol + j%3}
+		}
+	}
+	return us
+}
+
+// rowUnit, colUnit and boxUnit return the units a cell belongs in
+func rowUnit(row int) Unit      { return units[row] }
+func colUnit(col int) Unit      { return units[9+col] }
+func boxUnit(row, col int) Unit { return units[18+boxIndex(row, col)] }
+
+// sameCell reports if two cells have the same row and column
+func sameCell(c1, c2 Cell) bool {
+	return c1.row == c2.row && c1.col == c2.col
+}
+
+// sees reports if two different cells share a row, column or box
+func sees(c1, c2 Cell) bool {
+	if sameCell(c1, c2) {
+		return false
+	}
+	return c1.row == c2.row || c1.col == c2.col ||
+		boxIndex(c1.row, c1.col) == boxIndex(c2.row, c2.col)
+}
+
+// peers returns the 20 cells sharing a unit with a cell
+func peers(row, col int) []Cell {
+	var ps []Cell
+	c := Cell{row: row, col: col}
+	for r := 0; r < 9; r++ {
+		for cl := 0; cl < 9; cl++ {
+			if sees(c, Cell{row: r, col: cl}) {
+				ps = append(ps, Cell{row: r, col: cl})
+			}
+		}
+	}
+	return ps
+}
+
+// Step is one deduction made by the logical solver
+type Step struct {
+	Technique  Technique
+	Units      []Unit // units the pattern was found in
+	Cells      []Cell // cells forming the pattern
+	Digits     []int  // digits the pattern is about
+	Placed     []Cell // cells solved; Number is the number placed
+	Eliminated []Cell // Number is the mark removed from the cell
+	Chain      []Link // inferences leading to the conclusion, if any
+}
+
+// e.g. naked pair [2 7] in row 4 at [40][45]: [41]-2 [48]-7
+func (s Step) String() string {
+	var sb strings.Builder
+	sb.WriteString(s.Technique.String())
+	if len(s.Digits) > 0 {
+		fmt.Fprintf(&sb, " %v", s.Digits)
+	}
+	for i, u := range s.Units {
+		if i == 0 {
+			sb.WriteString(" in ")
+		} else {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(u.String())
+	}
+	if len(s.Cells) > 0 {
+		sb.WriteString(" at ")
+		for _, c := range s.Cells {
+			sb.WriteString(c.String())
+		}
+	}
+	sb.WriteString(":")
+	for _, c := range s.Placed {
+		fmt.Fprintf(&sb, " %s=%d", c, c.Number)
+	}
+	for _, c := range s.Eliminated {
+		fmt.Fprintf(&sb, " %s-%d", c, c.Number)
+	}
+	if len(s.Chain) > 0 {
+		sb.WriteString(" by")
+		for _, l := range s.Chain {
+			fmt.Fprintf(&sb, " %s", l)
+		}
+	}
+	return sb.String()
+}
+
+// finders of logical steps, simplest first
+var finders = []func(b *Board) (Step, bool){
+	findHiddenSingle,
+	findNakedSingle,
+	findPointing,
+	findBoxLine,
+	func(b *Board) (Step, bool) { return findNakedSubset(b, 2) },
+	func(b *Board) (Step, bool) { return findFish(b, 2) },
+	func(b *Board) (Step, bool) { return findHiddenSubset(b, 2) },
+	func(b *Board) (Step, bool) { return findNakedSubset(b, 3) },
+	func(b *Board) (Step, bool) { return findFish(b, 3) },
+	func(b *Board) (Step, bool) { return findHiddenSubset(b, 3) },
+	findXYWing,
+	findXYZWing,
+	findWWing,
+	func(b *Board) (Step, bool) { return findNakedSubset(b, 4) },
+	func(b *Board) (Step, bool) { return findFish(b, 4) },
+	func(b *Board) (Step, bool) { return findHiddenSubset(b, 4) },
+	findColoring,
+	findXCycle,
+	findAIC,
+	findUnitForcingChain,
+	findCellForcingChain,
+}
+
+// NextStep finds the simplest logical step using the board's marks;
+// the board is not changed. Marks must be set, see MarkCells.
+func (b *Board) NextStep() (Step, bool) {
+	for _, find := range finders {
+		if s, ok := find(b); ok {
+			return s, true
+		}
+	}
+	return Step{}, false
+}
+
+// Apply a step on the board; place numbers and remove marks
+func (b *Board) Apply(s Step) {
+	for _, c := range s.Placed {
+		b.place(c.row, c.col, c.Number)
+	}
+	for _, c := range s.Eliminated {
+		b.removeMark(c.row, c.col, c.Number)
+	}
+}
+
+// place a number on a cell and remove it from its peers' marks
+func (b *Board) place(row, col, n int) {
+	b[row][col].Number = n
+	b[row][col].Marks = nil
+	for _, p := range peers(row, col) {
+		b.removeMark(p.row, p.col, n)
+	}
+}
+
+// LogicSolve solves a copy of the board using logical steps only;
+// returns the steps taken and the resulting board, which is
+// not complete if the known techniques were not enough
+func (b *Board) LogicSolve() ([]Step, Board) {
+	var steps []Step
+	l := *b
+	l.MarkCells()
+	for {
+		s, ok := l.NextStep()
+		if !ok {
+			break
+		}
+		l.Apply(s)
+		steps = append(steps, s)
+	}
+	return steps, l
+}
+
+// remove mark number for a cell; reports if the mark was there
+func (b *Board) removeMark(row, col, n int) bool {
+	marks := b[row][col].Marks
+	for i := 0; i < len(marks); i++ {
+		if marks[i] == n {
+			// new slice; copied boards may share the old one
+			m := make([]int, 0, len(marks)-1)
+			m = append(m, marks[:i]...)
+			b[row][col].Marks = append(m, marks[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// HasMark checks if a cell has a mark
+func (c Cell) HasMark(n int) bool {
+	for _, m := range c.Marks {
+		if m == n {
+			return true
+		}
+	}
+	return false
+}
+
+// cell's marks as a bit mask; bit n set for mark n
+func (c Cell) markMask() uint16 {
+	var mask uint16
+	for _, m := range c.Marks {
+		mask |= 1 << m
+	}
+	return mask
+}
+
+// digits set in a bit mask, in ascending order
+func digits(mask uint16) []int {
+	var ds []int
+	for n := 1; n < 10; n++ {
+		if mask&(1<<n) != 0 {
+			ds = append(ds, n)
+		}
+	}
+	return ds
+}
+
+// combinations returns all k-sized combinations of indexes 0 to n-1
+func combinations(n, k int) [][]int {
+	var combos [][]int
+	combo := make([]int, k)
+	var pick func(start, i int)
+	pick = func(start, i int) {
+		if i == k {
+			combos = append(combos, append([]int(nil), combo...))
+			return
+		}
+		for j := start; j <= n-(k-i); j++ {
+			combo[i] = j
+			pick(j+1, i+1)
+		}
+	}
+	if k > 0 && k <= n {
+		pick(0, 0)
+	}
+	return combos
+}
+
+// positions of a mark in a unit; only empty cells are checked
+func (b *Board) positions(u Unit, n int) []Cell {
+	var cells []Cell
+	for _, c := range u.cells {
+		if b[c.row][c.col].Number == 0 && b[c.row][c.col].HasMark(n) {
+			cells = append(cells, c)
+		}
+	}
+	return cells
+}
+
+// placed reports if a number is already set in a unit
+func (b *Board) placed(u Unit, n int) bool {
+	for _, c := range u.cells {
+		if b[c.row][c.col].Number == n {
+			return true
+		}
+	}
+	return false
+}
+
+// eliminate collects the marks in mask of the given cells,
+// skipping cells in except
+func (b *Board) eliminate(cells []Cell, mask uint16, except []Cell) []Cell {
+	var elim []Cell
+out:
+	for _, c := range cells {
+		for _, e := range except {
+			if sameCell(c, e) {
+				continue out
+			}
+		}
+		if b[c.row][c.col].Number > 0 {
+			continue
+		}
+		for _, n := range b[c.row][c.col].Marks {
+			if mask&(1<<n) != 0 {
+				elim = append(elim, Cell{row: c.row, col: c.col, Number: n})
+			}
+		}
+	}
+	return elim
+}
+
+// hidden single: a number fits in only one cell of a unit;
+// boxes are checked first, as done when cross-hatching
+func findHiddenSingle(b *Board) (Step, bool) {
+	for i := 0; i < 27; i++ {
+		u := units[(18+i)%27] // boxes, rows, columns
+		for n := 1; n < 10; n++ {
+			cells := b.positions(u, n)
+			if len(cells) != 1 {
+				continue
+			}
+			c := cells[0]
+			return Step{
+				Technique: HiddenSingle,
+				Units:     []Unit{u},
+				Cells:     cells,
+				Digits:    []int{n},
+				Placed:    []Cell{{row: c.row, col: c.col, Number: n}},
+			}, true
+		}
+	}
+	return Step{}, false
+}
+
+// naked single: a cell with only one mark
+func findNakedSingle(b *Board) (Step, bool) {
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			c := b[row][col]
+			if c.Number > 0 || len(c.Marks) != 1 {
+				continue
+			}
+			return Step{
+				Technique: NakedSingle,
+				Units:     []Unit{rowUnit(row), colUnit(col), boxUnit(row, col)},
+				Cells:     []Cell{{row: row, col: col}},
+				Digits:    []int{c.Marks[0]},
+				Placed:    []Cell{{row: row, col: col, Number: c.Marks[0]}},
+			}, true
+		}
+	}
+	return Step{}, false
+}
+
+// pointing pair (or triple): a number's marks in a box are all
+// in one row or column, so the number is removed from the rest
+// of that row or column
+func findPointing(b *Board) (Step, bool) {
+	for _, bu := range units[18:] {
+		for n := 1; n < 10; n++ {
+			cells := b.positions(bu, n)
+			if len(cells) < 2 {
+				continue
+			}
+			for _, line := range []Unit{rowUnit(cells[0].row), colUnit(cells[0].col)} {
+				if !inUnit(line, cells) {
+					continue
+				}
+				elim := b.eliminate(line.cells[:], 1<<n, bu.cells[:])
+				if len(elim) > 0 {
+					return Step{
+						Technique:  PointingPair,
+						Units:      []Unit{bu, line},
+						Cells:      cells,
+						Digits:     []int{n},
+						Eliminated: elim,
+					}, true
+				}
+			}
+		}
+	}
+	return Step{}, false
+}
+
+// box/line reduction: a number's marks in a row or column are all
+// in one box, so the number is removed from the rest of that box
+func findBoxLine(b *Board) (Step, bool) {
+	for _, line := range units[:18] {
+		for n := 1; n < 10; n++ {
+			cells := b.positions(line, n)
+			if len(cells) < 2 {
+				continue
+			}
+			bu := boxUnit(cells[0].row, cells[0].col)
+			if !inUnit(bu, cells) {
+				continue
+			}
+			elim := b.eliminate(bu.cells[:], 1<<n, line.cells[:])
+			if len(elim) > 0 {
+				return Step{
+					Technique:  BoxLineReduction,
+					Units:      []Unit{line, bu},
+					Cells:      cells,
+					Digits:     []int{n},
+					Eliminated: elim,
+				}, true
+			}
+		}
+	}
+	return Step{}, false
+}
+
+// inUnit reports if all cells belong in a unit
+func inUnit(u Unit, cells []Cell) bool {
+	for _, c := range cells {
+		found := false
+		for _, uc := range u.cells {
+			if sameCell(c, uc) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+var nakedSubsets = map[int]Technique{2: NakedPair, 3: NakedTriple, 4: NakedQuad}
+var hiddenSubsets = map[int]Technique{2: HiddenPair, 3: HiddenTriple, 4: HiddenQuad}
+
+// naked subset: k cells of a unit with only k marks between them;
+// those marks are removed from the rest of the unit
+func findNakedSubset(b *Board, k int) (Step, bool) {
+	for _, u := range units {
+		var cells []Cell
+		for _, c := range u.cells {
+			cell := b[c.row][c.col]
+			if cell.Number == 0 && len(cell.Marks) >= 2 && len(cell.Marks) <= k {
+				cells = append(cells, c)
+			}
+		}
+		for _, combo := range combinations(len(cells), k) {
+			var mask uint16
+			subset := make([]Cell, k)
+			for i, j := range combo {
+				subset[i] = cells[j]
+				mask |= b[cells[j].row][cells[j].col].markMask()
+			}
+			if bits.OnesCount16(mask) != k {
+				continue
+			}
+			elim := b.eliminate(u.cells[:], mask, subset)
+			if len(elim) > 0 {
+				return Step{
+					Technique:  nakedSubsets[k],
+					Units:      []Unit{u},
+					Cells:      subset,
+					Digits:     digits(mask),
+					Eliminated: elim,
+				}, true
+			}
+		}
+	}
+	return Step{}, false
+}
+
+// hidden subset: k numbers of a unit fit in only k cells;
+// all other marks are removed from those cells
+func findHiddenSubset(b *Board, k int) (Step, bool) {
+	for _, u := range units {
+		var ns []int
+		var where [10]uint16 // bit i set if number fits in u.cells[i]
+		for n := 1; n < 10; n++ {
+			if b.placed(u, n) {
+				continue
+			}
+			for i, c := range u.cells {
+				if b[c.row][c.col].Number == 0 && b[c.row][c.col].HasMark(n) {
+					where[n] |= 1 << i
+				}
+			}
+			if where[n] != 0 {
+				ns = append(ns, n)
+			}
+		}
+		for _, combo := range combinations(len(ns), k) {
+			var cellMask, mask uint16
+			for _, j := range combo {
+				cellMask |= where[ns[j]]
+				mask |= 1 << ns[j]
+			}
+			if bits.OnesCount16(cellMask) != k {
+				continue
+			}
+			var subset []Cell
+			for i, c := range u.cells {
+				if cellMask&(1<<i) != 0 {
+					subset = append(subset, c)
+				}
+			}
+			elim := b.eliminate(subset, 0x3FE&^mask, nil)
+			if len(elim) > 0 {
+				return Step{
+					Technique:  hiddenSubsets[k],
+					Units:      []Unit{u},
+					Cells:      subset,
+					Digits:     digits(mask),
+					Eliminated: elim,
+				}, true
+			}
+		}
+	}
+	return Step{}, false
+}
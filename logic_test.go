@@ -0,0 +1,249 @@
+package dokusu
+
+import (
+	"testing"
+)
+
+// puzzles in one-line format, 0 or . for empty cells
+var testPuzzles = []string{
+	"003020600900305001001806400008102900700000008006708200002609500800203009005010300",
+	"000000010400000000020000000000050407008000300001090000300400200050100000000806000",
+	"8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4..",
+	"4.....8.5.3..........7......2.....6.....8.4......1.......6.3.7.5..2.....1.4......",
+	"1.......2.9.4...5...6...7...5.9.3.......7.......85..4.7.....6...3...9.8...2.....1",
+}
+
+// parse a one-line puzzle
+func parseBoard(t *testing.T, s string) Board {
+	b := NewBoard()
+	if len(s) != 81 {
+		t.Fatalf("puzzle has %d cells", len(s))
+	}
+	for i, ch := range s {
+		if ch >= '1' && ch <= '9' {
+			b[i/9][i%9].Number = int(ch - '0')
+		}
+	}
+	return b
+}
+
+// markedBoard makes an empty board with all marks set,
+// except for the cells given
+func markedBoard(marks map[[2]int][]int) Board {
+	b := NewBoard()
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			if m, ok := marks[[2]int{row, col}]; ok {
+				b[row][col].Marks = m
+				continue
+			}
+			b[row][col].Marks = []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+		}
+	}
+	return b
+}
+
+// remove a number from the marks of all cells of a unit
+// except for the cells given
+func unmark(b *Board, u Unit, n int, except ...[2]int) {
+out:
+	for _, c := range u.cells {
+		for _, e := range except {
+			if c.row == e[0] && c.col == e[1] {
+				continue out
+			}
+		}
+		b.removeMark(c.row, c.col, n)
+	}
+}
+
+// check that step eliminated exactly the marks wanted
+func checkEliminated(t *testing.T, s Step, want map[[2]int][]int) {
+	got := make(map[[2]int][]int)
+	for _, c := range s.Eliminated {
+		got[[2]int{c.row, c.col}] = append(got[[2]int{c.row, c.col}], c.Number)
+	}
+	if len(got) != len(want) {
+		t.Errorf("%s: eliminated in %d cells; want %d", s, len(got), len(want))
+	}
+	for k, ns := range want {
+		if len(got[k]) != len(ns) {
+			t.Errorf("%s: eliminated %v from [%d%d]; want %v", s, got[k], k[0], k[1], ns)
+			continue
+		}
+		for i := range ns {
+			if got[k][i] != ns[i] {
+				t.Errorf("%s: eliminated %v from [%d%d]; want %v", s, got[k], k[0], k[1], ns)
+			}
+		}
+	}
+}
+
+func TestHiddenSingle(t *testing.T) {
+	b := markedBoard(nil)
+	unmark(&b, boxUnit(4, 4), 3, [2]int{4, 4})
+
+	s, ok := b.NextStep()
+	if !ok || s.Technique != HiddenSingle {
+		t.Fatalf("got %s; want hidden single", s)
+	}
+	if len(s.Placed) != 1 || s.Placed[0].row != 4 || s.Placed[0].col != 4 || s.Placed[0].Number != 3 {
+		t.Errorf("got %s; want 3 placed in [44]", s)
+	}
+}
+
+func TestNakedSingle(t *testing.T) {
+	b := markedBoard(map[[2]int][]int{{4, 4}: {6}})
+
+	s, ok := b.NextStep()
+	if !ok || s.Technique != NakedSingle {
+		t.Fatalf("got %s; want naked single", s)
+	}
+	if len(s.Placed) != 1 || s.Placed[0].row != 4 || s.Placed[0].col != 4 || s.Placed[0].Number != 6 {
+		t.Errorf("got %s; want 6 placed in [44]", s)
+	}
+
+	b.Apply(s)
+	for _, p := range peers(4, 4) {
+		if b[p.row][p.col].HasMark(6) {
+			t.Errorf("mark 6 left in %s", p)
+		}
+	}
+}
+
+func TestPointing(t *testing.T) {
+	b := markedBoard(nil)
+	unmark(&b, boxUnit(0, 0), 5, [2]int{0, 0}, [2]int{0, 1})
+
+	s, ok := findPointing(&b)
+	if !ok {
+		t.Fatal("pointing pair not found")
+	}
+	want := make(map[[2]int][]int)
+	for col := 3; col < 9; col++ {
+		want[[2]int{0, col}] = []int{5}
+	}
+	checkEliminated(t, s, want)
+}
+
+func TestBoxLine(t *testing.T) {
+	b := markedBoard(nil)
+	unmark(&b, rowUnit(0), 5, [2]int{0, 0}, [2]int{0, 1})
+
+	s, ok := findBoxLine(&b)
+	if !ok {
+		t.Fatal("box/line reduction not found")
+	}
+	want := make(map[[2]int][]int)
+	for row := 1; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			want[[2]int{row, col}] = []int{5}
+		}
+	}
+	checkEliminated(t, s, want)
+}
+
+func TestNakedSubsets(t *testing.T) {
+	var tests = []struct {
+		k     int
+		marks map[[2]int][]int
+		want  Technique
+		from  int // first column eliminated from, in row 0
+		elim  []int
+	}{
+		{2, map[[2]int][]int{{0, 0}: {2, 7}, {0, 1}: {2, 7}}, NakedPair, 2, []int{2, 7}},
+		{3, map[[2]int][]int{{0, 0}: {1, 2}, {0, 1}: {2, 3}, {0, 2}: {1, 3}}, NakedTriple, 3, []int{1, 2, 3}},
+		{4, map[[2]int][]int{{0, 0}: {1, 2}, {0, 1}: {2, 3}, {0, 2}: {3, 4}, {0, 3}: {1, 4}}, NakedQuad, 4, []int{1, 2, 3, 4}},
+	}
+
+	for _, test := range tests {
+		b := markedBoard(test.marks)
+		s, ok := findNakedSubset(&b, test.k)
+		if !ok || s.Technique != test.want {
+			t.Errorf("got %s; want %s", s, test.want)
+			continue
+		}
+		want := make(map[[2]int][]int)
+		for col := test.from; col < 9; col++ {
+			want[[2]int{0, col}] = test.elim
+		}
+		checkEliminated(t, s, want)
+	}
+}
+
+func TestHiddenSubsets(t *testing.T) {
+	var tests = []struct {
+		cells [][2]int
+		ns    []int
+		want  Technique
+	}{
+		{[][2]int{{0, 0}, {0, 1}}, []int{2, 7}, HiddenPair},
+		{[][2]int{{0, 0}, {0, 4}, {0, 8}}, []int{1, 5, 9}, HiddenTriple},
+		{[][2]int{{0, 0}, {0, 3}, {0, 6}, {0, 8}}, []int{2, 4, 6, 8}, HiddenQuad},
+	}
+
+	for _, test := range tests {
+		b := markedBoard(nil)
+		for _, n := range test.ns {
+			unmark(&b, rowUnit(0), n, test.cells...)
+		}
+
+		s, ok := findHiddenSubset(&b, len(test.cells))
+		if !ok || s.Technique != test.want {
+			t.Errorf("got %s; want %s", s, test.want)
+			continue
+		}
+		want := make(map[[2]int][]int)
+		for _, c := range test.cells {
+			var other []int
+			for n := 1; n < 10; n++ {
+				if !in(test.ns, n) {
+					other = append(other, n)
+				}
+			}
+			want[c] = other
+		}
+		checkEliminated(t, s, want)
+	}
+}
+
+// in reports if n is in ns
+func in(ns []int, n int) bool {
+	for _, m := range ns {
+		if m == n {
+			return true
+		}
+	}
+	return false
+}
+
+// every logical step must agree with the puzzle's solution
+func TestLogicSolve(t *testing.T) {
+	for i, p := range testPuzzles {
+		b := parseBoard(t, p)
+		solution, err := b.Solve()
+		if err != nil {
+			t.Fatalf("puzzle #%d: %s", i, err)
+		}
+
+		steps, l := b.LogicSolve()
+		used := make(map[Technique]int)
+		for _, s := range steps {
+			used[s.Technique]++
+			for _, c := range s.Placed {
+				if solution[c.row][c.col].Number != c.Number {
+					t.Errorf("puzzle #%d: %s: wrong placement in %s", i, s, c)
+				}
+			}
+			for _, c := range s.Eliminated {
+				if solution[c.row][c.col].Number == c.Number {
+					t.Errorf("puzzle #%d: %s: solution eliminated from %s", i, s, c)
+				}
+			}
+		}
+		t.Logf("puzzle #%d: %d steps, complete: %v, techniques: %v", i, len(steps), l.IsComplete(), used)
+		if i == 0 && !l.IsComplete() {
+			t.Errorf("puzzle #%d not solved using singles", i)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package dokusu
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// MoveKind is what a move does to a cell
+type MoveKind int
+
+const (
+	PlaceMove  MoveKind = iota // set the cell's number, 0 erases it
+	MarkMove                   // add a candidate mark
+	UnmarkMove                 // remove a candidate mark
+)
+
+// Move is a player's action on a cell; rows and columns
+// are numbered 0 to 8, as printed around the board
+type Move struct {
+	Row    int
+	Col    int
+	Kind   MoveKind
+	Number int
+}
+
+// e.g. r4c7=5, r4c7+5 or r4c7-5
+func (m Move) String() string {
+	sign := "="
+	switch m.Kind {
+	case MarkMove:
+		sign = "+"
+	case UnmarkMove:
+		sign = "-"
+	}
+	return fmt.Sprintf("r%dc%d%s%d", m.Row, m.Col, sign, m.Number)
+}
+
+// moves are either r4c7=5 or 47 5; a number may be
+// prefixed with + or - to add or remove a candidate mark
+var (
+	moveRC    = regexp.MustCompile(`^r([0-8])c([0-8])\s*([=+-])\s*([0-9])$`)
+	moveShort = regexp.MustCompile(`^([0-8])([0-8])\s+([+-]?)([0-9])$`)
+)
+
+var errMove = errors.New("moves are like r4c7=5, 47 5 or 47 -5 (remove candidate)")
+
+// ParseMove parses a move typed by the player
+func ParseMove(s string) (Move, error) {
+	parts := moveRC.FindStringSubmatch(s)
+	if parts == nil {
+		parts = moveShort.FindStringSubmatch(s)
+	}
+	if parts == nil {
+		return Move{}, errMove
+	}
+
+	m := Move{}
+	m.Row, _ = strconv.Atoi(parts[1])
+	m.Col, _ = strconv.Atoi(parts[2])
+	m.Number, _ = strconv.Atoi(parts[4])
+	switch parts[3] {
+	case "+":
+		m.Kind = MarkMove
+	case "-":
+		m.Kind = UnmarkMove
+	}
+	if m.Kind != PlaceMove && m.Number == 0 {
+		return Move{}, errors.New("candidate marks are numbers from 1 to 9")
+	}
+
+	return m, nil
+}
+
+// Move applies a player's move on the board; givens cannot
+// change. A number placed that clashes with another one is
+// still set, and the clash is returned as a *ConflictError
+func (b *Board) Move(m Move) error {
+	c := &b[m.Row][m.Col]
+	if c.Given {
+		return fmt.Errorf("cell %s is a given", Cell{row: m.Row, col: m.Col})
+	}
+	switch m.Kind {
+	case MarkMove:
+		if c.Number > 0 {
+			return fmt.Errorf("cell %s is already set", Cell{row: m.Row, col: m.Col})
+		}
+		b.addMark(m.Row, m.Col, m.Number)
+
+	case UnmarkMove:
+		b.removeMark(m.Row, m.Col, m.Number)
+
+	default:
+		c.Number = 0
+		if m.Number == 0 {
+			return nil
+		}
+		found := b.checkNum(m.Number, m.Row, m.Col)
+		c.Number = m.Number
+		c.Marks = nil
+		if found != nil {
+			c.Invalid = true
+			return found
+		}
+	}
+
+	return nil
+}
+
+// FlagInvalid flags cells whose number clashes with another cell's
+func (b *Board) FlagInvalid() {
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			n := b[row][col].Number
+			if n == 0 {
+				continue
+			}
+			b[row][col].Number = 0
+			b[row][col].Invalid = b.checkNum(n, row, col) != nil
+			b[row][col].Number = n
+		}
+	}
+}
+
+// moves are saved as text, e.g. r4c7=5
+func (m Move) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Move) UnmarshalText(text []byte) error {
+	mv, err := ParseMove(string(text))
+	if err != nil {
+		return err
+	}
+	*m = mv
+	return nil
+}
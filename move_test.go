@@ -0,0 +1,92 @@
+package dokusu
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMove(t *testing.T) {
+	var tests = []struct {
+		input string
+		want  Move
+		err   bool
+	}{
+		{"r4c7=5", Move{4, 7, PlaceMove, 5}, false},
+		{"r4c7 = 5", Move{4, 7, PlaceMove, 5}, false},
+		{"r0c0=0", Move{0, 0, PlaceMove, 0}, false},
+		{"r4c7+5", Move{4, 7, MarkMove, 5}, false},
+		{"r4c7-5", Move{4, 7, UnmarkMove, 5}, false},
+		{"47 5", Move{4, 7, PlaceMove, 5}, false},
+		{"47 -5", Move{4, 7, UnmarkMove, 5}, false},
+		{"47 +5", Move{4, 7, MarkMove, 5}, false},
+		{"47  0", Move{4, 7, PlaceMove, 0}, false},
+		{"47 -0", Move{}, true},
+		{"r9c1=5", Move{}, true},
+		{"47 15", Move{}, true},
+		{"475", Move{}, true},
+		{"h", Move{}, true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseMove(test.input)
+		if (err != nil) != test.err {
+			t.Errorf("ParseMove(%q) error = %v; want error: %v", test.input, err, test.err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseMove(%q) = %v; want %v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestMove(t *testing.T) {
+	b := NewBoard()
+	err := b.Load(puzzleFile)
+	if err != nil {
+		t.Fatalf("error loading puzzle file: %s", err)
+	}
+
+	// [10] can only be 6
+	if err := b.Move(Move{1, 0, PlaceMove, 6}); err != nil {
+		t.Errorf("move r1c0=6: %s", err)
+	}
+	if b[1][0].Number != 6 || b[1][0].Invalid {
+		t.Errorf("[10] = %d, Invalid: %v; want 6", b[1][0].Number, b[1][0].Invalid)
+	}
+
+	// 5 is in [00]
+	err = b.Move(Move{1, 1, PlaceMove, 5})
+	var ce *ConflictError
+	if !errors.As(err, &ce) || !sameCell(ce.Cell, b[0][0]) || ce.Kind != BoxUnit {
+		t.Errorf("move r1c1=5: got error %v", err)
+	}
+	if b[1][1].Number != 5 || !b[1][1].Invalid {
+		t.Errorf("[11] = %d, Invalid: %v; want 5 and invalid", b[1][1].Number, b[1][1].Invalid)
+	}
+	b.Clear()
+	b.FlagInvalid()
+	t.Logf("\n%s", b.Grid())
+	if !b[0][0].Invalid || !b[1][1].Invalid || b[1][0].Invalid {
+		t.Error("clashing cells not flagged")
+	}
+
+	// erase it
+	if err := b.Move(Move{1, 1, PlaceMove, 0}); err != nil || b[1][1].Number != 0 {
+		t.Errorf("move r1c1=0: %v, [11] = %d", err, b[1][1].Number)
+	}
+	b.FlagInvalid()
+	if b[0][0].Invalid {
+		t.Error("[00] still flagged")
+	}
+
+	// candidate marks
+	b.Move(Move{1, 1, MarkMove, 4})
+	b.Move(Move{1, 1, MarkMove, 7})
+	b.Move(Move{1, 1, UnmarkMove, 4})
+	if len(b[1][1].Marks) != 1 || b[1][1].Marks[0] != 7 {
+		t.Errorf("[11] marks = %v; want [7]", b[1][1].Marks)
+	}
+	if err := b.Move(Move{0, 0, MarkMove, 4}); err == nil {
+		t.Error("marked a cell already set")
+	}
+}
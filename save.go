@@ -0,0 +1,196 @@
+package dokusu
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+// saveVersion is the version of the save file format written
+const saveVersion = 2
+
+// savedCell is a cell as kept in a save file
+type savedCell struct {
+	Number int
+	Given  bool  `json:",omitempty"`
+	Marks  []int `json:",omitempty"` // the player's pencil marks
+}
+
+// saveFile is the format of save files; puzzle files and
+// older saves are a bare board, which are read as well
+// as puzzles in text
+type saveFile struct {
+	Version int
+	Cells   [9][9]savedCell
+	Elapsed float64 `json:",omitempty"` // seconds played
+	History []Move  `json:",omitempty"` // moves made, oldest first
+	Undone  int     `json:",omitempty"` // moves at the end of History undone
+
+	Base *[9][9]savedCell `json:",omitempty"` // the givens the moves start from
+}
+
+// readSave reads a save or puzzle file
+func readSave(f string) (saveFile, error) {
+	j, err := ioutil.ReadFile(f)
+	if err != nil {
+		return saveFile{}, err
+	}
+
+	s, err := decodeSave(j)
+	if err != nil {
+		return saveFile{}, fmt.Errorf("%s: %w", f, err)
+	}
+	return s, nil
+}
+
+// decodeSave decodes a save, a bare board or a puzzle
+// in text, see parseText; boards must have 9 rows of 9
+// cells, and no fields other than a cell's
+func decodeSave(j []byte) (saveFile, error) {
+	var s saveFile
+	j = bytes.TrimSpace(j)
+	switch {
+	case bytes.HasPrefix(j, []byte("[")):
+		// a bare board
+		var rows [][]json.RawMessage
+		if err := json.Unmarshal(j, &rows); err != nil {
+			return saveFile{}, err
+		}
+		if err := checkShape(rows); err != nil {
+			return saveFile{}, err
+		}
+		var b Board
+		if err := unmarshalStrict(j, &b); err != nil {
+			return saveFile{}, err
+		}
+		s.Cells = b.cells()
+		return s, nil
+
+	case !bytes.HasPrefix(j, []byte("{")):
+		b, err := ParseText(string(j))
+		if err != nil {
+			return saveFile{}, err
+		}
+		s.Cells = b.cells()
+		return s, nil
+	}
+
+	var shape struct{ Cells, Base [][]json.RawMessage }
+	if err := json.Unmarshal(j, &shape); err != nil {
+		return saveFile{}, err
+	}
+	if err := checkShape(shape.Cells); err != nil {
+		return saveFile{}, err
+	}
+	if shape.Base != nil {
+		if err := checkShape(shape.Base); err != nil {
+			return saveFile{}, fmt.Errorf("base: %w", err)
+		}
+	}
+	if err := unmarshalStrict(j, &s); err != nil {
+		return saveFile{}, err
+	}
+	if s.Version > saveVersion {
+		return saveFile{}, fmt.Errorf("save version %d is newer than %d", s.Version, saveVersion)
+	}
+	if len(s.History) > 0 && s.Base == nil {
+		return saveFile{}, errors.New("moves saved without the board they start from")
+	}
+	if s.Undone < 0 || s.Undone > len(s.History) {
+		return saveFile{}, fmt.Errorf("%d moves undone out of %d", s.Undone, len(s.History))
+	}
+	return s, nil
+}
+
+// unmarshalStrict is json.Unmarshal failing on unknown fields
+func unmarshalStrict(j []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(j))
+	d.DisallowUnknownFields()
+	return d.Decode(v)
+}
+
+// writeSave writes the board to a file along with the
+// rest of a save: the time played, the moves made and
+// the board they started from
+func (b *Board) writeSave(f string, s saveFile) error {
+	s.Version = saveVersion
+	s.Cells = b.cells()
+	j, err := json.MarshalIndent(s, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(f, j, 0600)
+}
+
+// cells of the board as they are saved
+func (b *Board) cells() [9][9]savedCell {
+	var cells [9][9]savedCell
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			c := b[row][col]
+			cells[row][col] = savedCell{Number: c.Number, Given: c.Given}
+			if c.Number == 0 && len(c.Marks) > 0 {
+				cells[row][col].Marks = append([]int(nil), c.Marks...)
+			}
+		}
+	}
+	return cells
+}
+
+// set the board to saved cells; if none is marked as given,
+// as in bare boards and saves from before givens were kept,
+// all numbers are givens
+func (b *Board) setCells(cells [9][9]savedCell) {
+	*b = NewBoard()
+	marked := false
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			c := cells[row][col]
+			b[row][col].Number = c.Number
+			b[row][col].Given = c.Given
+			b[row][col].Marks = append([]int(nil), c.Marks...)
+			marked = marked || c.Given
+		}
+	}
+	if !marked {
+		b.MarkGivens()
+	}
+	b.FlagInvalid()
+}
+
+// Load a puzzle from a file: a save, a bare board in JSON
+// or text, see ParseText. The puzzle is checked, see Check;
+// if it is invalid it is still loaded, so that it can be
+// repaired, and a *PuzzleError is returned.
+func (b *Board) Load(f string) error {
+	s, err := readSave(f)
+	if err != nil {
+		return err
+	}
+
+	b.setCells(s.Cells)
+	if err := b.Check(false); err != nil {
+		return fmt.Errorf("%s: %w", f, err)
+	}
+	return nil
+}
+
+// Decode a puzzle read like Load does; an invalid puzzle is
+// returned along with its *PuzzleError
+func Decode(data []byte) (Board, error) {
+	s, err := decodeSave(data)
+	if err != nil {
+		return Board{}, err
+	}
+	b := NewBoard()
+	b.setCells(s.Cells)
+	return b, b.Check(false)
+}
+
+// Save the board to a file, with its marks
+func (b *Board) Save(f string) error {
+	return b.writeSave(f, saveFile{})
+}
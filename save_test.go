@@ -0,0 +1,108 @@
+package dokusu
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveGame(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+
+	b := NewBoard()
+	if err := b.Load(puzzleFile); err != nil {
+		t.Fatalf("error loading puzzle: %s", err)
+	}
+	b.MarkGivens()
+	g := NewGame(b)
+	g.elapsed = 90 * time.Second
+	moves := []Move{
+		{Row: 0, Col: 3, Kind: MarkMove, Number: 4},
+		{Row: 0, Col: 3, Kind: MarkMove, Number: 8},
+		{Row: 0, Col: 4, Kind: PlaceMove, Number: 7},
+	}
+	for _, m := range moves {
+		if err := g.Move(m); err != nil {
+			t.Fatalf("move %s: %s", m, err)
+		}
+	}
+	g.Board[1][1].Selected = true // highlights are not saved
+	if err := g.Save(stateFile); err != nil {
+		t.Fatalf("error saving game: %s", err)
+	}
+	if !g.Board[1][1].Selected {
+		t.Error("saving cleared the board's highlights")
+	}
+
+	r, err := LoadGame(stateFile)
+	if err != nil {
+		t.Fatalf("error loading game: %s", err)
+	}
+	if !sameNumbers(r.Board, g.Board) {
+		t.Error("numbers were not restored")
+	}
+	if !r.Board[0][0].Given || r.Board[0][4].Given {
+		t.Error("givens were not restored")
+	}
+	if m := r.Board[0][3].Marks; len(m) != 2 || m[0] != 4 || m[1] != 8 {
+		t.Errorf("marks restored as %v, want [4 8]", m)
+	}
+	if r.Board[1][1].Selected {
+		t.Error("highlights were restored")
+	}
+	if r.elapsed != 90*time.Second {
+		t.Errorf("elapsed restored as %s, want 1m30s", r.elapsed)
+	}
+	if len(r.history) != len(moves) {
+		t.Fatalf("restored %d moves, want %d", len(r.history), len(moves))
+	}
+	for i, m := range moves {
+		if r.history[i] != m {
+			t.Errorf("move %d restored as %s, want %s", i, r.history[i], m)
+		}
+	}
+}
+
+func TestLoadBareBoard(t *testing.T) {
+	// puzzle files, like older saves, are just a board
+	g, err := LoadGame(puzzleFile)
+	if err != nil {
+		t.Fatalf("error loading puzzle: %s", err)
+	}
+	if g.Board[0][2].Number != 1 || g.elapsed != 0 || len(g.history) != 0 {
+		t.Errorf("bare board loaded wrong: %d, %s, %v", g.Board[0][2].Number, g.elapsed, g.history)
+	}
+	if g.Board[4][4].row != 4 || g.Board[4][4].col != 4 {
+		t.Error("cell coordinates were not set")
+	}
+}
+
+func TestLoadBareBoardGivens(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+
+	// a bare board marks no givens: its numbers are taken as givens
+	g, err := LoadGame(puzzleFile)
+	if err != nil {
+		t.Fatalf("error loading puzzle: %s", err)
+	}
+	if !g.Board[0][0].Given || g.Board[0][3].Given {
+		t.Fatal("numbers of a bare board were not loaded as givens")
+	}
+	if err := g.Move(Move{Row: 0, Col: 0, Number: 0}); err == nil || g.Board[0][0].Number == 0 {
+		t.Error("a given of a bare board was erased")
+	}
+
+	// a clashing move leaves the save valid
+	n := g.Board[0][0].Number
+	g.Move(Move{Row: 0, Col: 3, Number: n})
+	if err := g.Save(stateFile); err != nil {
+		t.Fatalf("error saving game: %s", err)
+	}
+	r, err := LoadGame(stateFile)
+	if err != nil {
+		t.Fatalf("error loading game with a clashing move: %s", err)
+	}
+	if r.Board[0][3].Given || r.Board[0][3].Number != n {
+		t.Error("clashing move was not restored as the player's")
+	}
+}
@@ -0,0 +1,252 @@
+package dokusu
+
+import (
+	"fmt"
+	"math/bits"
+	"math/rand"
+)
+
+// UnsolvableError is returned when a board has no solution
+type UnsolvableError struct {
+	Reason string
+}
+
+func (e *UnsolvableError) Error() string {
+	return "board is unsolvable: " + e.Reason
+}
+
+// solver keeps the used numbers of every row, column and 3x3 box
+// as bit masks; bit n is set when number n is used
+type solver struct {
+	cells [9][9]int
+	rows  [9]uint16
+	cols  [9]uint16
+	boxes [9]uint16
+	rand  *rand.Rand // if set, numbers are tried in random order
+}
+
+// boxIndex returns the index (0-8) of the 3x3 box a cell belongs in
+func boxIndex(row, col int) int {
+	return row/3*3 + col/3
+}
+
+// newSolver loads the board's numbers into a solver;
+// fails if a number is out of range or already used
+func newSolver(b *Board) (*solver, error) {
+	s := &solver{}
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			n := b[row][col].Number
+			if n == 0 {
+				continue
+			}
+			if n < 0 || n > 9 {
+				return nil, &UnsolvableError{fmt.Sprintf("number %d in cell [%d%d] out of range", n, row, col)}
+			}
+			if s.used(row, col)&(1<<n) != 0 {
+				return nil, &UnsolvableError{fmt.Sprintf("number %d in cell [%d%d] already used", n, row, col)}
+			}
+			s.set(row, col, n)
+		}
+	}
+	return s, nil
+}
+
+// used returns the numbers used in a cell's row, column and box
+func (s *solver) used(row, col int) uint16 {
+	return s.rows[row] | s.cols[col] | s.boxes[boxIndex(row, col)]
+}
+
+// set number n on a cell
+func (s *solver) set(row, col, n int) {
+	s.cells[row][col] = n
+	s.rows[row] |= 1 << n
+	s.cols[col] |= 1 << n
+	s.boxes[boxIndex(row, col)] |= 1 << n
+}
+
+// unset a cell's number
+func (s *solver) unset(row, col int) {
+	n := s.cells[row][col]
+	s.cells[row][col] = 0
+	s.rows[row] &^= 1 << n
+	s.cols[col] &^= 1 << n
+	s.boxes[boxIndex(row, col)] &^= 1 << n
+}
+
+// next finds the empty cell with the fewest free numbers;
+// ok is false when there are no empty cells left
+func (s *solver) next() (row, col int, free uint16, ok bool) {
+	best := 10
+	for r := 0; r < 9; r++ {
+		for c := 0; c < 9; c++ {
+			if s.cells[r][c] > 0 {
+				continue
+			}
+			f := ^s.used(r, c) & 0x3FE // bits 1 to 9
+			count := bits.OnesCount16(f)
+			if count < best {
+				row, col, free, ok = r, c, f, true
+				best = count
+				if count < 2 { // cannot do better than this
+					return
+				}
+			}
+		}
+	}
+	return
+}
+
+// search fills empty cells by backtracking and calls found
+// for every solution; stops as soon as found returns false.
+// Returns false if the search was stopped.
+func (s *solver) search(found func() bool) bool {
+	row, col, free, ok := s.next()
+	if !ok {
+		return found()
+	}
+	numbers := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	if s.rand != nil {
+		numbers = shuffle(s.rand, numbers)
+	}
+	for _, n := range numbers {
+		if free&(1<<n) == 0 {
+			continue
+		}
+		s.set(row, col, n)
+		more := s.search(found)
+		s.unset(row, col)
+		if !more {
+			return false
+		}
+	}
+	return true
+}
+
+// fill copies solver's numbers to the empty cells of a board,
+// marking them as solved
+func (s *solver) fill(b *Board) {
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			if b[row][col].Number == 0 {
+				b[row][col].Number = s.cells[row][col]
+				b[row][col].Solved = true
+			}
+		}
+	}
+}
+
+// Solve returns a solved copy of the board;
+// the board itself is never changed
+func (b *Board) Solve() (Board, error) {
+	s, err := newSolver(b)
+	if err != nil {
+		return Board{}, err
+	}
+
+	solved := *b
+	found := false
+	s.search(func() bool {
+		s.fill(&solved)
+		found = true
+		return false // first solution is enough
+	})
+	if !found {
+		return Board{}, &UnsolvableError{"no solution exists"}
+	}
+
+	return solved, nil
+}
+
+// IsComplete reports if all cells are set and no number is repeated
+// in any row, column or box
+func (b *Board) IsComplete() bool {
+	s, err := newSolver(b)
+	if err != nil {
+		return false
+	}
+	_, _, _, empty := s.next()
+	return !empty
+}
+
+// Uniqueness tells if a board has none, one or more solutions
+type Uniqueness int
+
+const (
+	NoSolution Uniqueness = iota
+	UniqueSolution
+	MultipleSolutions
+)
+
+func (u Uniqueness) String() string {
+	switch u {
+	case NoSolution:
+		return "no solution"
+	case UniqueSolution:
+		return "unique"
+	default:
+		return "multiple solutions"
+	}
+}
+
+// Solutions returns up to limit solutions of the board;
+// a limit less than 1 means no limit
+func (b *Board) Solutions(limit int) ([]Board, error) {
+	s, err := newSolver(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []Board
+	s.search(func() bool {
+		solved := *b
+		s.fill(&solved)
+		found = append(found, solved)
+		return limit < 1 || len(found) < limit
+	})
+
+	return found, nil
+}
+
+// CountSolutions counts the board's solutions, stopping at limit;
+// a limit less than 1 means no limit
+func (b *Board) CountSolutions(limit int) int {
+	s, err := newSolver(b)
+	if err != nil {
+		return 0
+	}
+
+	count := 0
+	s.search(func() bool {
+		count++
+		return limit < 1 || count < limit
+	})
+
+	return count
+}
+
+// Uniqueness checks if the board has exactly one solution;
+// up to two solutions are returned, differing when there are more
+func (b *Board) Uniqueness() (Uniqueness, []Board) {
+	found, err := b.Solutions(2)
+	if err != nil || len(found) == 0 {
+		return NoSolution, nil
+	}
+	if len(found) == 1 {
+		return UniqueSolution, found
+	}
+	return MultipleSolutions, found
+}
+
+// Diff returns the cells in which two boards have different numbers
+func Diff(b1, b2 *Board) []Cell {
+	var cells []Cell
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			if b1[row][col].Number != b2[row][col].Number {
+				cells = append(cells, Cell{row: row, col: col})
+			}
+		}
+	}
+	return cells
+}
@@ -0,0 +1,158 @@
+package dokusu
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSolve(t *testing.T) {
+	b := NewBoard()
+	err := b.Load(puzzleFile)
+	if err != nil {
+		t.Fatalf("error loading puzzle file: %s", err)
+	}
+	before := b
+
+	solved, err := b.Solve()
+	if err != nil {
+		t.Fatalf("cannot solve puzzle: %s", err)
+	}
+	t.Logf("\n%s", solved.Grid())
+
+	if !solved.IsComplete() {
+		t.Error("solution is not complete")
+	}
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			if b[row][col].Number != before[row][col].Number {
+				t.Errorf("puzzle changed in [%d%d]", row, col)
+			}
+			n := b[row][col].Number
+			if n > 0 && solved[row][col].Number != n {
+				t.Errorf("given %d in [%d%d] changed to %d", n, row, col, solved[row][col].Number)
+			}
+		}
+	}
+}
+
+func TestSolveEmpty(t *testing.T) {
+	b := NewBoard()
+	solved, err := b.Solve()
+	if err != nil {
+		t.Fatalf("cannot solve empty board: %s", err)
+	}
+	if !solved.IsComplete() {
+		t.Error("solution is not complete")
+	}
+}
+
+func TestSolveUnsolvable(t *testing.T) {
+	var tests = []struct {
+		name  string
+		cells [][3]int // row, col, number
+	}{
+		{"out of range", [][3]int{{0, 0, 15}}},
+		{"same row", [][3]int{{0, 0, 5}, {0, 8, 5}}},
+		{"same column", [][3]int{{0, 4, 7}, {6, 4, 7}}},
+		{"same box", [][3]int{{3, 3, 2}, {5, 5, 2}}},
+		// [00] can only hold 9, but 9 is used in box 1
+		{"no solution", [][3]int{
+			{0, 1, 1}, {0, 2, 2}, {0, 3, 3}, {0, 4, 4},
+			{0, 5, 5}, {0, 6, 6}, {0, 7, 7}, {0, 8, 8}, {1, 1, 9},
+		}},
+	}
+
+	for _, test := range tests {
+		b := NewBoard()
+		for _, c := range test.cells {
+			b[c[0]][c[1]].Number = c[2]
+		}
+		before := b
+
+		solved, err := b.Solve()
+		var unsolvable *UnsolvableError
+		if !errors.As(err, &unsolvable) {
+			t.Errorf("%s: got error %v; want UnsolvableError", test.name, err)
+		}
+		if !sameNumbers(solved, Board{}) {
+			t.Errorf("%s: got a solution for an unsolvable board", test.name)
+		}
+		if !sameNumbers(b, before) {
+			t.Errorf("%s: board changed", test.name)
+		}
+	}
+}
+
+// sameNumbers reports if two boards have the same numbers in all cells
+func sameNumbers(b1, b2 Board) bool {
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			if b1[row][col].Number != b2[row][col].Number {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestCountSolutions(t *testing.T) {
+	b := NewBoard()
+	err := b.Load(puzzleFile)
+	if err != nil {
+		t.Fatalf("error loading puzzle file: %s", err)
+	}
+
+	if got := b.CountSolutions(0); got != 1 {
+		t.Errorf("countSolutions(0) = %d for puzzle; want 1", got)
+	}
+
+	empty := NewBoard()
+	for _, limit := range []int{1, 2, 10} {
+		if got := empty.CountSolutions(limit); got != limit {
+			t.Errorf("countSolutions(%d) = %d for empty board; want %d", limit, got, limit)
+		}
+	}
+
+	b[0][0].Number = 15
+	if got := b.CountSolutions(0); got != 0 {
+		t.Errorf("countSolutions(0) = %d for invalid board; want 0", got)
+	}
+}
+
+func TestUniqueness(t *testing.T) {
+	b := NewBoard()
+	err := b.Load(puzzleFile)
+	if err != nil {
+		t.Fatalf("error loading puzzle file: %s", err)
+	}
+
+	u, found := b.Uniqueness()
+	if u != UniqueSolution || len(found) != 1 {
+		t.Errorf("uniqueness() = %s with %d solutions; want unique", u, len(found))
+	}
+
+	// remove givens until there is more than one solution
+	for row := 0; row < 9 && u == UniqueSolution; row++ {
+		for col := 0; col < 9 && u == UniqueSolution; col++ {
+			b[row][col].Number = 0
+			u, found = b.Uniqueness()
+		}
+	}
+	if u != MultipleSolutions || len(found) != 2 {
+		t.Fatalf("uniqueness() = %s with %d solutions; want multiple", u, len(found))
+	}
+	cells := Diff(&found[0], &found[1])
+	if len(cells) == 0 {
+		t.Error("two solutions do not differ")
+	}
+	for _, c := range cells {
+		if b[c.row][c.col].Number != 0 {
+			t.Errorf("solutions differ in given %s", c)
+		}
+	}
+
+	b[0][0].Number, b[0][1].Number = 5, 5
+	if u, _ := b.Uniqueness(); u != NoSolution {
+		t.Errorf("uniqueness() = %s; want no solution", u)
+	}
+}
@@ -0,0 +1,316 @@
+package tui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kamhlos/dokusu"
+)
+
+// StateFile is where games are saved while playing
+var StateFile = "state.json"
+
+// user input
+var scanner = bufio.NewScanner(os.Stdin)
+
+// session is a game being played and how it is shown
+type session struct {
+	*dokusu.Game
+	hint  dokusu.Step // the step hinted at
+	stage int         // hint stage shown, 0 for none
+	large bool        // candidate view, see PrintLarge
+}
+
+// get user input
+func getInput() string {
+	fmt.Printf("\tYour choice: ")
+	if !scanner.Scan() {
+		return "x" // no more input
+	}
+	return scanner.Text()
+}
+
+// get a command while playing
+func getCommand() string {
+	for {
+		fmt.Print("Enter a number, a move (e.g. r4c7=5, 47 5, 47 -5), (h)int, (u)ndo, re(d)o, (b)ack to no errors, (r)eset, (v)iew candidates, e(x)it or Ctrl-c to exit: ")
+		if !scanner.Scan() {
+			return "x" // no more input
+		}
+		input := strings.TrimSpace(scanner.Text())
+		if input != "" {
+			return input
+		}
+	}
+}
+
+// warn if the puzzle is not a proper sudoku, i.e it has
+// no solution or more than one; for the latter show two
+// of the solutions and the cells where they differ
+func warnUnique(b *dokusu.Board) {
+	u, found := b.Uniqueness()
+	switch u {
+	case dokusu.NoSolution:
+		fmt.Printf("\tWarning: puzzle has no solution\n")
+
+	case dokusu.MultipleSolutions:
+		fmt.Printf("\tWarning: puzzle has multiple solutions; two of them differ in:")
+		for _, c := range dokusu.Diff(&found[0], &found[1]) {
+			fmt.Printf(" %s", c)
+			found[0][c.Row()][c.Col()].Active = true
+			found[1][c.Row()][c.Col()].Active = true
+		}
+		fmt.Printf("\n")
+		Print(&found[0])
+		Print(&found[1])
+	}
+}
+
+// print the board in the view chosen
+func (g *session) print() {
+	if g.large {
+		PrintLarge(&g.Board)
+		return
+	}
+	Print(&g.Board)
+}
+
+// play the game line by line until the player exits
+func (g *session) play() {
+	g.Start()
+	g.print()
+	for {
+		cmd := getCommand()
+		if cmd == "x" {
+			return
+		}
+		msg := g.command(cmd)
+		g.print()
+		if msg != "" {
+			fmt.Printf("\t%s\n", msg)
+		}
+	}
+}
+
+// command carries out a player's command: a number to cross-hatch,
+// a move, or one of (h)int, (u)ndo, re(d)o, (b)ack to no errors,
+// (r)eset and (v)iew candidates; returns a message for the player, if any
+func (g *session) command(cmd string) string {
+	b := &g.Board
+	if cmd == "h" {
+		// each hint request reveals a bit more of the next step
+		if g.stage == 0 {
+			s, ok := b.Hint()
+			if !ok {
+				return "No hint available"
+			}
+			g.hint = s
+		}
+		g.stage++
+		b.Clear()
+		b.FlagInvalid()
+		msg := "Hint: " + b.ShowHint(g.hint, g.stage)
+		if g.stage == dokusu.HintAnswer {
+			g.stage = 0
+		}
+		return msg
+	}
+	if cmd == "v" {
+		g.large = !g.large
+		return ""
+	}
+	g.stage = 0
+	b.Clear()
+	b.FlagInvalid()
+
+	var msg string
+	switch cmd {
+	case "r":
+		// back to the givens, the moves can still be redone
+		g.GoTo(0)
+	case "u":
+		if !g.Undo() {
+			return "Nothing to undo"
+		}
+	case "d":
+		if !g.Redo() {
+			return "Nothing to redo"
+		}
+	case "b":
+		msg = fmt.Sprintf("Undid %d moves", g.BackToValid())
+
+	default:
+		num, err := strconv.Atoi(cmd)
+		if err == nil {
+			return g.hatch(num)
+		}
+
+		// not a number, so it must be a move
+		m, err := dokusu.ParseMove(cmd)
+		if err != nil {
+			return err.Error()
+		}
+		return g.makeMove(m)
+	}
+
+	if err := g.Save(StateFile); err != nil {
+		return fmt.Sprintf("error saving: %s", err)
+	}
+	return msg
+}
+
+// cross-hatch a number and list the cells it is forced in
+func (g *session) hatch(num int) string {
+	if num < 1 || num > 9 {
+		return "Must enter a number from 1 to 9"
+	}
+	forced := g.Board.CrossHatch(num)
+	if len(forced) == 0 {
+		return ""
+	}
+	var cells []string
+	for _, c := range forced {
+		cells = append(cells, c.String())
+	}
+	return fmt.Sprintf("Number %d is forced in: %s", num, strings.Join(cells, " "))
+}
+
+// makeMove makes a player's move and saves the game; returns
+// why the move failed, blinking the cell clashed with if any,
+// or a note the puzzle is solved
+func (g *session) makeMove(m dokusu.Move) string {
+	var msg string
+	err := g.Move(m)
+	var ce *dokusu.ConflictError
+	if errors.As(err, &ce) {
+		msg = fmt.Sprintf("Conflict: %s", err)
+	} else if err != nil {
+		msg = err.Error()
+	}
+	if err := g.Save(StateFile); err != nil {
+		return fmt.Sprintf("error saving: %s", err)
+	}
+	g.Board.FlagInvalid()
+	if ce != nil {
+		g.Board[ce.Cell.Row()][ce.Cell.Col()].Blink = true
+	}
+	if g.Board.IsComplete() {
+		msg = fmt.Sprintf("Solved in %s!", g.PlayTime())
+	}
+	return msg
+}
+
+// PlayPuzzle starts a new game on the puzzle in a file
+func PlayPuzzle(f string) error {
+	b := dokusu.NewBoard()
+	err := b.Load(f)
+	if err != nil && !repair(&b, err) {
+		return err
+	}
+	warnUnique(&b)
+	fmt.Printf("\tNew puzzle, difficulty: %s\n", b.Grade())
+	start(dokusu.NewGame(b))
+	return nil
+}
+
+// PlayRandom starts a new game on a random puzzle
+func PlayRandom() {
+	p := dokusu.Generate(dokusu.GenOptions{Symmetry: dokusu.RotationalSymmetry})
+	fmt.Printf("\tNew random puzzle, seed %d, difficulty: %s\n", p.Seed, p.Board.Grade())
+	start(dokusu.NewGame(p.Board))
+}
+
+// PlayDaily starts a new game on today's puzzle
+func PlayDaily() {
+	today := time.Now().UTC()
+	p := dokusu.Daily(today)
+	fmt.Printf("\tPuzzle of %s, difficulty: %s\n", today.Format("2006-01-02"), p.Board.Grade())
+	start(dokusu.NewGame(p.Board))
+}
+
+// Resume the game saved in the state file
+func Resume() error {
+	g, err := dokusu.LoadGame(StateFile)
+	if err != nil {
+		// a repaired game starts over from the board saved,
+		// the player's numbers on it taken as moves
+		b := dokusu.NewBoard()
+		if !repair(&b, b.Load(StateFile)) {
+			return err
+		}
+		g = dokusu.NewGame(b)
+	}
+	fmt.Printf("\tResuming after %s, %d moves made\n", g.PlayTime(), len(g.Moves()))
+	start(g)
+	return nil
+}
+
+// repair offers to repair an invalid puzzle loaded, or to
+// skip it; false if skipped or the error is not a *PuzzleError
+func repair(b *dokusu.Board, err error) bool {
+	var invalid *dokusu.PuzzleError
+	if !errors.As(err, &invalid) {
+		return false
+	}
+	fmt.Printf("\tInvalid puzzle:\n")
+	for _, p := range invalid.Problems {
+		fmt.Printf("\t  %s\n", p)
+	}
+	fmt.Printf("\tOptions: (r)epair, (s)kip\n")
+	for {
+		switch getInput() {
+		case "r":
+			fmt.Printf("\tCleared cells: %v\n", b.Repair())
+			return true
+		case "s", "x":
+			return false
+		}
+	}
+}
+
+// Menu offers to play a puzzle file, a random puzzle or the
+// day's one, or to resume the saved game, until the player exits
+func Menu(puzzle string) {
+	fmt.Printf("\tOptions: (n)ew, r(a)ndom, (d)aily, (r)esume, e(x)it\n")
+	input := getInput()
+	for {
+		switch input {
+		case "n":
+			// load puzzle from puzzle.json file
+			err := PlayPuzzle(puzzle)
+			if err != nil {
+				fmt.Printf("\tCannot play %s: %s\n", puzzle, err)
+			}
+			input = ""
+
+		case "a":
+			PlayRandom()
+			input = ""
+
+		case "d":
+			PlayDaily()
+			input = ""
+
+		case "r":
+			// load previously saved puzzle in state.json
+			err := Resume()
+			if err != nil {
+				fmt.Printf("\tCannot resume: %s\n", err)
+			}
+			input = ""
+
+		case "x":
+			return // exit program
+
+		default:
+			fmt.Printf("\tOptions: (n)ew, r(a)ndom, (d)aily, (r)esume, e(x)it\n")
+			input = getInput()
+
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/kamhlos/dokusu"
+)
+
+const (
+	cReset      = "0m"
+	cBright     = "1m"
+	cDim        = "2m"
+	cUnderscore = "4m"
+	cBlink      = "5m"
+	cReverse    = "7m"
+	cHidden     = "8m"
+
+	cFgBlack   = "30m"
+	cFgRed     = "31m"
+	cFgGreen   = "32m"
+	cFgYellow  = "33m"
+	cFgBlue    = "34m"
+	cFgMagenta = "35m"
+	cFgCyan    = "36m"
+	cFgWhite   = "37m"
+
+	cBgBlack   = "40m"
+	cBgRed     = "41m"
+	cBgGreen   = "42m"
+	cBgYellow  = "43m"
+	cBgBlue    = "44m"
+	cBgMagenta = "45m"
+	cBgCyan    = "46m"
+	cBgWhite   = "47m"
+)
+
+// content prints a cell's number depending on the cell's state
+// see structs for available colors
+func content(c dokusu.Cell) string {
+	number := " " // zero-numbered cells shown as empty
+	if c.Number > 0 {
+		number = fmt.Sprintf("%d", c.Number)
+	}
+
+	return "\033[0;" + style(c) + number + "\033[0m"
+}
+
+// style and color of a cell depending on its state
+func style(c dokusu.Cell) string {
+	var color, style string
+	color = cFgWhite // default is white foreground color
+
+	// givens are bold, numbers entered by the player cyan
+	if c.Given {
+		style = "1;"
+	} else if c.Number > 0 {
+		color = cFgCyan
+	}
+
+	if c.Invalid {
+		color = cFgRed
+	}
+	if c.Solved {
+		color = cFgYellow
+	}
+	if c.Active {
+		color = cFgMagenta
+	}
+	if c.Candid {
+		color = cBgGreen
+	}
+	if c.Blink {
+		color = cBlink
+	}
+	if c.Selected {
+		color = cBgBlue
+	}
+
+	return style + color
+}
+
+// mini prints one of the three lines of a cell in the candidate
+// view; a number is shown in the middle, or else the cell's marks
+// where they are on a phone keypad, i.e. 1 to 3 on the top line
+func mini(c dokusu.Cell, line int) string {
+	text := []byte("   ")
+	st := style(c)
+	if c.Number > 0 {
+		if line == 1 {
+			text[1] = byte('0' + c.Number)
+		}
+	} else {
+		st = "2;" + st // marks are dimmed
+		for _, n := range c.Marks {
+			if (n-1)/3 == line {
+				text[(n-1)%3] = byte('0' + n)
+			}
+		}
+	}
+
+	return "\033[0;" + st + string(text) + "\033[0m"
+}
+
+// Print prints the board with the cells contents if num not zero
+func Print(b *dokusu.Board) {
+	fmt.Printf("\n\n")
+	// START first row of boxes
+	fmt.Printf("\t  \033[0;2m" + "  0   1   2   3   4   5   6   7   8\n" + "\033[0m")
+	fmt.Printf("\t  \u250F\u2501\u2501\u2501\u252F\u2501\u2501\u2501\u252F\u2501\u2501\u2501\u2533\u2501\u2501\u2501\u252F\u2501\u2501\u2501\u252F\u2501\u2501\u2501\u2533\u2501\u2501\u2501\u252F\u2501\u2501\u2501\u252F\u2501\u2501\u2501\u2513\n")
+	// first row of numbers
+	printRow(b, 0)
+	fmt.Printf("\t  \u2520\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2542\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2542\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2528\n")
+	// second row of numbers
+	printRow(b, 1)
+	fmt.Printf("\t  \u2520\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2542\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2542\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2528\n")
+	// third row of numbers
+	printRow(b, 2)
+	fmt.Printf("\t  \u2523\u2501\u2501\u2501\u253F\u2501\u2501\u2501\u253F\u2501\u2501\u2501\u254B\u2501\u2501\u2501\u253F\u2501\u2501\u2501\u253F\u2501\u2501\u2501\u254B\u2501\u2501\u2501\u253F\u2501\u2501\u2501\u253F\u2501\u2501\u2501\u252B\n")
+	// END first row of boxes
+
+	// REPEAT
+	// START second row of boxes (no border)
+	// first row of numbers
+	printRow(b, 3)
+	fmt.Printf("\t  \u2520\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2542\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2542\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2528\n")
+	// second row of numbers
+	printRow(b, 4)
+	fmt.Printf("\t  \u2520\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2542\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2542\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2528\n")
+	// third row of numbers
+	printRow(b, 5)
+	fmt.Printf("\t  \u2523\u2501\u2501\u2501\u253F\u2501\u2501\u2501\u253F\u2501\u2501\u2501\u254B\u2501\u2501\u2501\u253F\u2501\u2501\u2501\u253F\u2501\u2501\u2501\u254B\u2501\u2501\u2501\u253F\u2501\u2501\u2501\u253F\u2501\u2501\u2501\u252B\n")
+	// END second row of boxes
+	// REPEAT
+	// START third row of boxes (no border)
+
+	// first row of numbers
+	printRow(b, 6)
+	fmt.Printf("\t  \u2520\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2542\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2542\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2528\n")
+	// second row of numbers
+	printRow(b, 7)
+	fmt.Printf("\t  \u2520\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2542\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2542\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2528\n")
+	// third row of numbers
+	printRow(b, 8)
+	fmt.Printf("\t  \u2517\u2501\u2501\u2501\u2537\u2501\u2501\u2501\u2537\u2501\u2501\u2501\u253B\u2501\u2501\u2501\u2537\u2501\u2501\u2501\u2537\u2501\u2501\u2501\u253B\u2501\u2501\u2501\u2537\u2501\u2501\u2501\u2537\u2501\u2501\u2501\u251B\n")
+	// END third row of boxes
+
+	fmt.Printf("\n\n")
+}
+
+// print each row between cell borders separately
+// so the cell's numbers are printed (with color)
+// replace 2502 with 250A or 2506 for vertical lines
+func printRow(b *dokusu.Board, row int) {
+	// print row number row in gray color
+	fmt.Printf("\t\033[0;2m%d\033[0m ", row)
+
+	// this one line printed; broken into three for better readability
+	fmt.Printf("\u2503 %s \u2502 %s \u2502 %s \u2503", content(b[row][0]), content(b[row][1]), content(b[row][2]))
+	fmt.Printf(" %s \u2502 %s \u2502 %s \u2503", content(b[row][3]), content(b[row][4]), content(b[row][5]))
+	fmt.Printf(" %s \u2502 %s \u2502 %s \u2503\n", content(b[row][6]), content(b[row][7]), content(b[row][8]))
+}
+
+// PrintLarge prints the board in the candidate view, each cell
+// three lines high showing its marks; borders are as in Print
+func PrintLarge(b *dokusu.Board) {
+	thin := "\t  \u2520\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2542\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2542\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u253C\u2500\u2500\u2500\u2528\n"
+	thick := "\t  \u2523\u2501\u2501\u2501\u253F\u2501\u2501\u2501\u253F\u2501\u2501\u2501\u254B\u2501\u2501\u2501\u253F\u2501\u2501\u2501\u253F\u2501\u2501\u2501\u254B\u2501\u2501\u2501\u253F\u2501\u2501\u2501\u253F\u2501\u2501\u2501\u252B\n"
+
+	fmt.Printf("\n\n")
+	fmt.Printf("\t  \033[0;2m" + "  0   1   2   3   4   5   6   7   8\n" + "\033[0m")
+	fmt.Printf("\t  \u250F\u2501\u2501\u2501\u252F\u2501\u2501\u2501\u252F\u2501\u2501\u2501\u2533\u2501\u2501\u2501\u252F\u2501\u2501\u2501\u252F\u2501\u2501\u2501\u2533\u2501\u2501\u2501\u252F\u2501\u2501\u2501\u252F\u2501\u2501\u2501\u2513\n")
+	for row := 0; row < 9; row++ {
+		for line := 0; line < 3; line++ {
+			printMiniRow(b, row, line)
+		}
+		switch {
+		case row == 8:
+			fmt.Printf("\t  \u2517\u2501\u2501\u2501\u2537\u2501\u2501\u2501\u2537\u2501\u2501\u2501\u253B\u2501\u2501\u2501\u2537\u2501\u2501\u2501\u2537\u2501\u2501\u2501\u253B\u2501\u2501\u2501\u2537\u2501\u2501\u2501\u2537\u2501\u2501\u2501\u251B\n")
+		case row%3 == 2:
+			fmt.Print(thick)
+		default:
+			fmt.Print(thin)
+		}
+	}
+
+	fmt.Printf("\n\n")
+}
+
+// prints one of the three lines of a row in the candidate view;
+// the row number is on the middle one
+func printMiniRow(b *dokusu.Board, row, line int) {
+	if line == 1 {
+		fmt.Printf("\t\033[0;2m%d\033[0m ", row)
+	} else {
+		fmt.Printf("\t  ")
+	}
+
+	fmt.Printf("\u2503")
+	for col := 0; col < 9; col++ {
+		border := "\u2502"
+		if col%3 == 2 {
+			border = "\u2503"
+		}
+		fmt.Printf("%s%s", mini(b[row][col], line), border)
+	}
+	fmt.Printf("\n")
+}
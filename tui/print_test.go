@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kamhlos/dokusu"
+)
+
+// a puzzle to play in tests
+const testPuzzle = "531009620000000000000006094096038100000000300700601040060800400105020000000000000"
+
+func testBoard(t *testing.T) dokusu.Board {
+	b, err := dokusu.ParseText(testPuzzle)
+	if err != nil {
+		t.Fatalf("cannot read test puzzle: %s", err)
+	}
+	return b
+}
+
+func TestPrint(t *testing.T) {
+	b := testBoard(t)
+	b.CrossHatch(5)
+	Print(&b)
+	PrintLarge(&b)
+}
+
+func TestGivensBold(t *testing.T) {
+	b := testBoard(t)
+	if err := b.Move(dokusu.Move{Row: 1, Col: 0, Kind: dokusu.PlaceMove, Number: 6}); err != nil {
+		t.Fatalf("move r1c0=6: %s", err)
+	}
+	if !strings.Contains(content(b[0][0]), "\033[0;1;") || strings.Contains(content(b[1][0]), "\033[0;1;") {
+		t.Error("givens not shown in bold")
+	}
+}
+
+func TestMini(t *testing.T) {
+	c := dokusu.Cell{Marks: []int{1, 5, 9}}
+	plain := func(s string) string {
+		return s[strings.Index(s, "m")+1 : strings.LastIndex(s, "\033")]
+	}
+	want := []string{"1  ", " 5 ", "  9"}
+	for line, w := range want {
+		if got := plain(mini(c, line)); got != w {
+			t.Errorf("marks line %d is %q, want %q", line, got, w)
+		}
+	}
+
+	c = dokusu.Cell{Number: 7, Marks: []int{1}}
+	want = []string{"   ", " 7 ", "   "}
+	for line, w := range want {
+		if got := plain(mini(c, line)); got != w {
+			t.Errorf("number line %d is %q, want %q", line, got, w)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package tui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/kamhlos/dokusu"
+)
+
+// terminal is the player's terminal in raw mode, read
+// key by key; stty is used so no packages are needed
+type terminal struct {
+	in    *bufio.Reader
+	saved string // stty settings to restore
+	width int
+	line  int // line of the cursor, counted from the board's top
+}
+
+// rawTerminal puts the terminal in raw mode; fails if
+// input is not a terminal
+func rawTerminal() (*terminal, error) {
+	fi, err := os.Stdin.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.Mode()&os.ModeCharDevice == 0 {
+		return nil, errors.New("input is not a terminal")
+	}
+
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	// keep output processing so \n still starts a new line
+	if _, err := stty("raw", "-echo", "opost"); err != nil {
+		return nil, err
+	}
+
+	t := &terminal{in: bufio.NewReader(os.Stdin), saved: saved, width: 80}
+	if size, err := stty("size"); err == nil {
+		f := strings.Fields(size)
+		if len(f) == 2 {
+			if w, err := strconv.Atoi(f[1]); err == nil && w > 0 {
+				t.width = w
+			}
+		}
+	}
+	return t, nil
+}
+
+// run stty on the terminal
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// restore the terminal as it was
+func (t *terminal) restore() {
+	stty(t.saved)
+}
+
+// key reads a key press; arrow keys are "up", "down", "left" and "right"
+func (t *terminal) key() (string, error) {
+	c, err := t.in.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	if c != 27 || t.in.Buffered() < 2 {
+		return string(c), nil
+	}
+
+	// escape sequences, e.g. ESC [ A for the up arrow
+	seq := make([]byte, 2)
+	t.in.Read(seq)
+	if seq[0] != '[' && seq[0] != 'O' {
+		return "", nil
+	}
+	switch seq[1] {
+	case 'A':
+		return "up", nil
+	case 'B':
+		return "down", nil
+	case 'C':
+		return "right", nil
+	case 'D':
+		return "left", nil
+	case '3': // delete is ESC [ 3 ~
+		t.in.ReadByte()
+		return "delete", nil
+	}
+	return "", nil
+}
+
+// moveTo moves the cursor to a line of the board and
+// a column of the screen, both counted from 1
+func (t *terminal) moveTo(line, col int) {
+	if line < t.line {
+		fmt.Printf("\033[%dA", t.line-line)
+	} else if line > t.line {
+		fmt.Printf("\033[%dB", line-t.line)
+	}
+	fmt.Printf("\033[%dG", col)
+	t.line = line
+}
+
+// first line of row 0 as laid out by Print and PrintLarge,
+// counting the two empty lines Print starts with
+const firstRowLine = 5
+
+// lines a row takes on the screen, its lower border included
+func (u *tui) rowLines() int {
+	if u.g.large {
+		return 4
+	}
+	return 2
+}
+
+// lines below the board: a message for the player, the keys
+// to use and the one Print leaves the cursor on
+func (u *tui) statusLine() int { return firstRowLine + 9*u.rowLines() }
+func (u *tui) keysLine() int   { return u.statusLine() + 1 }
+func (u *tui) lastLine() int   { return u.statusLine() + 2 }
+
+// screen position of a cell's number
+func (u *tui) cellPos(row, col int) (int, int) {
+	line := firstRowLine + row*u.rowLines()
+	if u.g.large {
+		line++ // the middle one of the cell's lines
+	}
+	return line, 13 + 4*col
+}
+
+// a cell as it is printed
+func (u *tui) cellText(row, col int) string {
+	c := u.g.Board[row][col]
+	if !u.g.large {
+		return content(c)
+	}
+	return mini(c, 0) + mini(c, 1) + mini(c, 2)
+}
+
+// draw a cell printed before
+func (u *tui) drawCell(row, col int) {
+	line, pos := u.cellPos(row, col)
+	if !u.g.large {
+		u.t.moveTo(line, pos)
+		fmt.Print(content(u.g.Board[row][col]))
+		return
+	}
+	for i := 0; i < 3; i++ {
+		u.t.moveTo(line-1+i, pos-1)
+		fmt.Print(mini(u.g.Board[row][col], i))
+	}
+}
+
+// print a line below the board, cut to fit the screen
+func (t *terminal) printLine(line int, s string) {
+	if r := []rune(s); len(r) >= t.width {
+		s = string(r[:t.width-1])
+	}
+	t.moveTo(line, 1)
+	fmt.Print("\033[2K" + s)
+}
+
+// tui is the cursor driven interface to a game
+type tui struct {
+	g        *session
+	t        *terminal
+	row, col int          // the cursor
+	pencil   bool         // digits add or remove candidate marks
+	shown    [9][9]string // cells as they are on the screen
+}
+
+const tuiKeys = "arrows/hjkl move, 1-9 place, 0 erase, (p)encil, (c)ross-hatch, (?)hint, (u)ndo, re(d)o, (b)ack, (r)eset, (v)iew, e(x)it"
+
+// playTUI plays the game in a raw terminal until the player exits
+func (g *session) playTUI(t *terminal) {
+	defer t.restore()
+	g.Start()
+	u := &tui{g: g, t: t}
+	g.Board.Clear()
+	g.Board.FlagInvalid()
+	u.draw()
+	u.status("")
+
+	for {
+		k, err := t.key()
+		if err != nil {
+			break
+		}
+		msg, quit := u.press(k)
+		if quit {
+			break
+		}
+		u.redraw()
+		u.status(msg)
+	}
+	t.moveTo(u.lastLine(), 1)
+	fmt.Print("\n")
+}
+
+// draw the whole board, over the one drawn before if any
+func (u *tui) draw() {
+	if u.t.line > 0 {
+		u.t.moveTo(1, 1)
+		fmt.Print("\033[J") // clear the screen below
+	}
+
+	// Print leaves the cursor below the board
+	u.g.print()
+	u.t.line = u.lastLine()
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			u.shown[row][col] = u.cellText(row, col)
+		}
+	}
+	u.t.printLine(u.keysLine(), tuiKeys)
+}
+
+// press handles a key; true if the player wants to exit
+func (u *tui) press(k string) (string, bool) {
+	b := &u.g.Board
+	switch k {
+	case "x", "q", "\x03": // ctrl-c
+		return "", true
+
+	case "up", "k":
+		u.row = (u.row + 8) % 9
+	case "down", "j":
+		u.row = (u.row + 1) % 9
+	case "left", "h":
+		u.col = (u.col + 8) % 9
+	case "right", "l":
+		u.col = (u.col + 1) % 9
+
+	case "p":
+		u.pencil = !u.pencil
+
+	case "v":
+		u.g.command(k)
+		u.draw()
+
+	case "?":
+		return u.g.command("h"), false
+
+	case "c":
+		n := b[u.row][u.col].Number
+		if n == 0 {
+			return "Cross-hatch needs a number in the cell", false
+		}
+		return u.g.command(strconv.Itoa(n)), false
+
+	case "u", "d", "b", "r":
+		return u.g.command(k), false
+
+	case "0", ".", " ", "\x7f", "delete":
+		return u.g.command(dokusu.Move{Row: u.row, Col: u.col}.String()), false
+
+	default:
+		n, err := strconv.Atoi(k)
+		if err != nil || n < 1 {
+			return "", false
+		}
+		m := dokusu.Move{Row: u.row, Col: u.col, Number: n}
+		if u.pencil {
+			m.Kind = dokusu.MarkMove
+			if b[u.row][u.col].HasMark(n) {
+				m.Kind = dokusu.UnmarkMove
+			}
+		}
+		return u.g.command(m.String()), false
+	}
+	return "", false
+}
+
+// redraw the cells that changed since last shown
+func (u *tui) redraw() {
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			c := u.cellText(row, col)
+			if c == u.shown[row][col] {
+				continue
+			}
+			u.drawCell(row, col)
+			u.shown[row][col] = c
+		}
+	}
+}
+
+// show a message, the mode and the cursor cell's marks,
+// then put the cursor back on its cell
+func (u *tui) status(msg string) {
+	mode := "place"
+	if u.pencil {
+		mode = "pencil"
+	}
+	s := fmt.Sprintf("[%s] [%d%d]", mode, u.row, u.col)
+	if marks := u.g.Board[u.row][u.col].Marks; len(marks) > 0 {
+		s += fmt.Sprintf(" marks %v", marks)
+	}
+	if msg != "" {
+		s += "  " + msg
+	}
+	u.t.printLine(u.statusLine(), s)
+	u.t.moveTo(u.cellPos(u.row, u.col))
+}
+
+// start playing in a raw terminal if there is one, or else line by line
+func start(g *dokusu.Game) {
+	s := &session{Game: g}
+	t, err := rawTerminal()
+	if err != nil {
+		s.play()
+		return
+	}
+	s.playTUI(t)
+}
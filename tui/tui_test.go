@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kamhlos/dokusu"
+)
+
+func TestPress(t *testing.T) {
+	saved := StateFile
+	StateFile = filepath.Join(t.TempDir(), "state.json")
+	defer func() { StateFile = saved }()
+
+	b := testBoard(t)
+	sol, err := b.Solve()
+	if err != nil {
+		t.Fatalf("puzzle cannot be solved: %s", err)
+	}
+	g := &session{Game: dokusu.NewGame(b)}
+	u := &tui{g: g}
+	row, col := 0, 3 // the first empty cell
+
+	// walk the cursor to the first empty cell, wrapping around
+	u.press("up")
+	u.press("left")
+	if u.row != 8 || u.col != 8 {
+		t.Fatalf("cursor at [%d%d], want [88]", u.row, u.col)
+	}
+	u.row, u.col = row, col
+
+	u.press("p")
+	u.press("3")
+	if !g.Board[row][col].HasMark(3) {
+		t.Error("pencil mode did not add a mark")
+	}
+	u.press("3")
+	if g.Board[row][col].HasMark(3) {
+		t.Error("pencil mode did not remove the mark")
+	}
+
+	u.press("p")
+	n := sol[row][col].Number
+	u.press(string(rune('0' + n)))
+	if g.Board[row][col].Number != n {
+		t.Errorf("number not placed, cell has %d", g.Board[row][col].Number)
+	}
+	u.press("delete")
+	if g.Board[row][col].Number != 0 {
+		t.Error("number not erased")
+	}
+	u.press("u")
+	if g.Board[row][col].Number != n {
+		t.Error("undo did not bring the number back")
+	}
+
+	// 5 clashes with [00] in the row, which blinks
+	if msg, _ := u.press("5"); !strings.HasPrefix(msg, "Conflict") || !g.Board[0][0].Blink {
+		t.Errorf("clash not shown: %q", msg)
+	}
+
+	// other errors are not conflicts
+	u.row, u.col = 0, 0
+	if msg, _ := u.press("delete"); msg != "cell [00] is a given" {
+		t.Errorf("erasing a given shows %q", msg)
+	}
+
+	if _, quit := u.press("x"); !quit {
+		t.Error("x did not exit")
+	}
+}
+
+func TestCellPos(t *testing.T) {
+	u := &tui{g: &session{}}
+	line, col := u.cellPos(0, 0)
+	if line != firstRowLine || col != 13 {
+		t.Errorf("cell [00] at %d,%d", line, col)
+	}
+	line, col = u.cellPos(8, 8)
+	if line != 21 || col != 45 || u.statusLine() != 23 {
+		t.Errorf("cell [88] at %d,%d, want 21,45", line, col)
+	}
+
+	// three lines for each cell, the number in the middle
+	u.g.large = true
+	line, col = u.cellPos(8, 8)
+	if line != 38 || col != 45 || u.statusLine() != 41 {
+		t.Errorf("large cell [88] at %d,%d, want 38,45", line, col)
+	}
+}
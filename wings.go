@@ -0,0 +1,164 @@
+package dokusu
+
+// cells with exactly n marks
+func (b *Board) cellsWithMarks(n int) []Cell {
+	var cells []Cell
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			if b[row][col].Number == 0 && len(b[row][col].Marks) == n {
+				cells = append(cells, Cell{row: row, col: col})
+			}
+		}
+	}
+	return cells
+}
+
+// eliminateSeen collects mark n of the cells that see all given cells
+func (b *Board) eliminateSeen(n int, cells ...Cell) []Cell {
+	var elim []Cell
+	for row := 0; row < 9; row++ {
+	next:
+		for col := 0; col < 9; col++ {
+			c := Cell{row: row, col: col}
+			if b[row][col].Number > 0 || !b[row][col].HasMark(n) {
+				continue
+			}
+			for _, s := range cells {
+				if !sees(c, s) {
+					continue next
+				}
+			}
+			elim = append(elim, Cell{row: row, col: col, Number: n})
+		}
+	}
+	return elim
+}
+
+// strongLinks returns pairs of cells that are the only places
+// for a number in some unit; one of them must hold the number
+func (b *Board) strongLinks(n int) [][2]Cell {
+	var links [][2]Cell
+	for _, u := range units {
+		pos := b.positions(u, n)
+		if len(pos) != 2 {
+			continue
+		}
+		dup := false
+		for _, l := range links { // same pair in a line and a box
+			if sameCell(l[0], pos[0]) && sameCell(l[1], pos[1]) {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			links = append(links, [2]Cell{pos[0], pos[1]})
+		}
+	}
+	return links
+}
+
+// xy-wing: a pivot cell with marks xy sees two pincers with marks
+// xz and yz; either pincer is z, so z is removed from cells
+// that see both pincers
+func findXYWing(b *Board) (Step, bool) {
+	pairs := b.cellsWithMarks(2)
+	for _, pivot := range pairs {
+		pm := b[pivot.row][pivot.col].markMask()
+		for i, p1 := range pairs {
+			for _, p2 := range pairs[i+1:] {
+				if !sees(pivot, p1) || !sees(pivot, p2) {
+					continue
+				}
+				m1 := b[p1.row][p1.col].markMask()
+				m2 := b[p2.row][p2.col].markMask()
+				z := m1 & m2 &^ pm
+				// pincers share z, and each one a different pivot mark
+				if z == 0 || m1&pm == 0 || m2&pm == 0 || m1&m2&pm != 0 || (m1|m2)&pm != pm {
+					continue
+				}
+				n := digits(z)[0]
+				elim := b.eliminateSeen(n, p1, p2)
+				if len(elim) > 0 {
+					return Step{
+						Technique:  XYWing,
+						Cells:      []Cell{pivot, p1, p2},
+						Digits:     digits(pm | z),
+						Eliminated: elim,
+					}, true
+				}
+			}
+		}
+	}
+	return Step{}, false
+}
+
+// xyz-wing: a pivot cell with marks xyz sees two pincers with marks
+// xz and yz; one of the three is z, so z is removed from cells
+// that see all of them
+func findXYZWing(b *Board) (Step, bool) {
+	pairs := b.cellsWithMarks(2)
+	for _, pivot := range b.cellsWithMarks(3) {
+		pm := b[pivot.row][pivot.col].markMask()
+		for i, p1 := range pairs {
+			for _, p2 := range pairs[i+1:] {
+				if !sees(pivot, p1) || !sees(pivot, p2) {
+					continue
+				}
+				m1 := b[p1.row][p1.col].markMask()
+				m2 := b[p2.row][p2.col].markMask()
+				z := m1 & m2
+				if m1 == m2 || m1&^pm != 0 || m2&^pm != 0 {
+					continue
+				}
+				n := digits(z)[0]
+				elim := b.eliminateSeen(n, pivot, p1, p2)
+				if len(elim) > 0 {
+					return Step{
+						Technique:  XYZWing,
+						Cells:      []Cell{pivot, p1, p2},
+						Digits:     digits(pm),
+						Eliminated: elim,
+					}, true
+				}
+			}
+		}
+	}
+	return Step{}, false
+}
+
+// w-wing: two cells with the same marks xy that do not see each other,
+// and a strong link on x whose ends see one cell each; one of the two
+// cells is y, so y is removed from cells that see both
+func findWWing(b *Board) (Step, bool) {
+	pairs := b.cellsWithMarks(2)
+	for i, c1 := range pairs {
+		for _, c2 := range pairs[i+1:] {
+			m := b[c1.row][c1.col].markMask()
+			if m != b[c2.row][c2.col].markMask() || sees(c1, c2) {
+				continue
+			}
+			ds := digits(m)
+			for k, x := range ds {
+				y := ds[1-k]
+				for _, l := range b.strongLinks(x) {
+					if sameCell(l[0], c1) || sameCell(l[0], c2) || sameCell(l[1], c1) || sameCell(l[1], c2) {
+						continue
+					}
+					if !(sees(l[0], c1) && sees(l[1], c2)) && !(sees(l[0], c2) && sees(l[1], c1)) {
+						continue
+					}
+					elim := b.eliminateSeen(y, c1, c2)
+					if len(elim) > 0 {
+						return Step{
+							Technique:  WWing,
+							Cells:      []Cell{c1, c2, l[0], l[1]},
+							Digits:     []int{x, y},
+							Eliminated: elim,
+						}, true
+					}
+				}
+			}
+		}
+	}
+	return Step{}, false
+}
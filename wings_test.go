@@ -0,0 +1,48 @@
+package dokusu
+
+import (
+	"testing"
+)
+
+func TestXYWing(t *testing.T) {
+	b := markedBoard(map[[2]int][]int{
+		{4, 4}: {1, 2}, // pivot
+		{4, 0}: {1, 3},
+		{1, 4}: {2, 3},
+	})
+
+	s, ok := findXYWing(&b)
+	if !ok {
+		t.Fatal("xy-wing not found")
+	}
+	checkEliminated(t, s, map[[2]int][]int{{1, 0}: {3}})
+}
+
+func TestXYZWing(t *testing.T) {
+	b := markedBoard(map[[2]int][]int{
+		{4, 4}: {1, 2, 3}, // pivot
+		{4, 3}: {1, 3},
+		{1, 4}: {2, 3},
+	})
+
+	s, ok := findXYZWing(&b)
+	if !ok {
+		t.Fatal("xyz-wing not found")
+	}
+	checkEliminated(t, s, map[[2]int][]int{{3, 4}: {3}, {5, 4}: {3}})
+}
+
+func TestWWing(t *testing.T) {
+	b := markedBoard(map[[2]int][]int{
+		{0, 0}: {1, 2},
+		{4, 8}: {1, 2},
+	})
+	// strong link on 2 in row 8, ends seeing [00] and [48]
+	unmark(&b, rowUnit(8), 2, [2]int{8, 0}, [2]int{8, 8})
+
+	s, ok := findWWing(&b)
+	if !ok {
+		t.Fatal("w-wing not found")
+	}
+	checkEliminated(t, s, map[[2]int][]int{{0, 8}: {1}, {4, 0}: {1}})
+}